@@ -0,0 +1,13 @@
+# 模型 ID 到展示信息的映射，未收录的模型直接显示原始 ID
+MODEL_INFO = {
+    "deepseek-chat": {"provider": "deepseek", "display_name": "DeepSeek V3"},
+    "deepseek-reasoner": {"provider": "deepseek", "display_name": "DeepSeek R1"},
+    "gpt-4o": {"provider": "openai", "display_name": "GPT-4o"},
+    "gpt-4o-mini": {"provider": "openai", "display_name": "GPT-4o mini"},
+}
+
+def model_info(model_id):
+    if not model_id:
+        return None
+    info = MODEL_INFO.get(model_id, {"provider": "unknown", "display_name": model_id})
+    return {"id": model_id, **info}
@@ -0,0 +1,13 @@
+import hmac
+from fastapi import Depends, HTTPException
+from fastapi.security import HTTPAuthorizationCredentials, HTTPBearer
+from app.config import settings
+
+bearer = HTTPBearer(auto_error=False)
+
+def require_admin(credentials: HTTPAuthorizationCredentials = Depends(bearer)):
+    """管理类接口校验 Authorization: Bearer <ADMIN_TOKEN>，未配置 ADMIN_TOKEN 时全部拒绝"""
+    if not settings.ADMIN_TOKEN or credentials is None:
+        raise HTTPException(status_code=401, detail="Unauthorized", headers={"WWW-Authenticate": "Bearer"})
+    if not hmac.compare_digest(credentials.credentials, settings.ADMIN_TOKEN):
+        raise HTTPException(status_code=403, detail="Forbidden")
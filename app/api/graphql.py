@@ -0,0 +1,144 @@
+from datetime import datetime
+from typing import List, Optional
+import strawberry
+from fastapi import Depends
+from sqlalchemy import func
+from sqlalchemy.orm import Session, defer
+from strawberry.fastapi import GraphQLRouter
+from strawberry.types import Info
+from app.api.pagination import clamp_limit
+from app.database import get_db
+from app.models.ai_analysis import AIAnalysis
+from app.models.crawl_history import CrawlHistory
+from app.models.repository import Repository, REPOSITORY_CATEGORIES
+
+@strawberry.type
+class Analysis:
+    content: Optional[str]
+    status: Optional[str]
+    model_version: Optional[str]
+    updated_at: Optional[datetime]
+
+@strawberry.type
+class RepositoryNode:
+    id: int
+    full_name: str
+    name: str
+    owner: str
+    owner_avatar_url: Optional[str]
+    owner_type: Optional[str]
+    description: Optional[str]
+    url: str
+    homepage: Optional[str]
+    stars: Optional[int]
+    forks: Optional[int]
+    contributor_count: Optional[int]
+    language: Optional[str]
+    ecosystem: Optional[str]
+    topics: Optional[str]
+    derived_topics: Optional[str]
+    category: Optional[str]
+    maturity: Optional[str]
+    last_pushed_at: Optional[datetime]
+    latest_release_tag: Optional[str]
+    latest_release_at: Optional[datetime]
+    analysis_status: Optional[str]
+    first_analyzed_at: Optional[datetime]
+    last_analyzed_at: Optional[datetime]
+
+    @strawberry.field
+    def analysis(self, info: Info) -> Optional[Analysis]:
+        db = info.context["db"]
+        row = db.query(AIAnalysis).filter(AIAnalysis.url == self.url).first()
+        if not row:
+            return None
+        return Analysis(
+            content=row.content,
+            status=row.status,
+            model_version=row.model_version,
+            updated_at=row.updated_at,
+        )
+
+@strawberry.type
+class Stats:
+    total_repositories: int
+    analyzed_repositories: int
+    total_crawls: int
+
+def to_node(repo) -> RepositoryNode:
+    return RepositoryNode(
+        id=repo.id,
+        full_name=repo.full_name,
+        name=repo.name,
+        owner=repo.owner,
+        owner_avatar_url=repo.owner_avatar_url,
+        owner_type=repo.owner_type,
+        description=repo.description,
+        url=repo.url,
+        homepage=repo.homepage,
+        stars=repo.stars,
+        forks=repo.forks,
+        contributor_count=repo.contributor_count,
+        language=repo.language,
+        ecosystem=repo.ecosystem,
+        topics=repo.topics,
+        derived_topics=repo.derived_topics,
+        category=repo.category,
+        maturity=repo.maturity,
+        last_pushed_at=repo.last_pushed_at,
+        latest_release_tag=repo.latest_release_tag,
+        latest_release_at=repo.latest_release_at,
+        analysis_status=repo.analysis_status,
+        first_analyzed_at=repo.first_analyzed_at,
+        last_analyzed_at=repo.last_analyzed_at,
+    )
+
+@strawberry.type
+class Query:
+    @strawberry.field
+    def repositories(
+        self,
+        info: Info,
+        q: Optional[str] = None,
+        language: Optional[str] = None,
+        category: Optional[str] = None,
+        skip: int = 0,
+        limit: Optional[int] = None,
+    ) -> List[RepositoryNode]:
+        if category and category not in REPOSITORY_CATEGORIES:
+            raise ValueError(f"category must be one of {', '.join(REPOSITORY_CATEGORIES)}")
+        db = info.context["db"]
+        query = db.query(Repository).options(defer(Repository.readme)).filter(Repository.deleted_at.is_(None))
+        if q:
+            query = query.filter(Repository.full_name.ilike(f"%{q}%"))
+        if language:
+            query = query.filter(Repository.language == language)
+        if category:
+            query = query.filter(Repository.category == category)
+        repos = query.order_by(Repository.stars.desc()).offset(max(skip, 0)).limit(clamp_limit(limit)).all()
+        return [to_node(r) for r in repos]
+
+    @strawberry.field
+    def repository(self, info: Info, id: int) -> Optional[RepositoryNode]:
+        db = info.context["db"]
+        repo = db.query(Repository).filter(Repository.id == id).first()
+        return to_node(repo) if repo else None
+
+    @strawberry.field
+    def stats(self, info: Info) -> Stats:
+        db = info.context["db"]
+        return Stats(
+            total_repositories=db.query(func.count(Repository.id)).filter(Repository.deleted_at.is_(None)).scalar(),
+            analyzed_repositories=db.query(func.count(AIAnalysis.id)).join(
+                Repository, Repository.url == AIAnalysis.url
+            ).filter(Repository.deleted_at.is_(None), AIAnalysis.content.isnot(None)).scalar(),
+            total_crawls=db.query(func.count(CrawlHistory.id)).scalar(),
+        )
+
+def get_context(db: Session = Depends(get_db)):
+    return {"db": db}
+
+schema = strawberry.Schema(query=Query)
+
+# 只读查询，与 REST 接口共用同一套模型
+router = GraphQLRouter(schema, context_getter=get_context)
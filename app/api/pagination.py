@@ -0,0 +1,29 @@
+from dataclasses import dataclass
+from typing import Optional
+from fastapi import Query
+from app.api.responses import envelope
+from app.config import settings
+
+@dataclass
+class Pagination:
+    skip: int
+    limit: int
+
+def clamp_limit(limit: Optional[int]) -> int:
+    """未传时使用 DEFAULT_PAGE_SIZE，截断到 [1, MAX_PAGE_SIZE]"""
+    if limit is None:
+        return settings.DEFAULT_PAGE_SIZE
+    # GraphQL 等不经过 Query 校验的调用方可能传入 0 或负数
+    return max(1, min(limit, settings.MAX_PAGE_SIZE))
+
+def pagination(
+    skip: int = Query(0, ge=0),
+    limit: Optional[int] = Query(None, ge=1, description="每页数量"),
+) -> Pagination:
+    return Pagination(skip=skip, limit=clamp_limit(limit))
+
+def paginate(query, page: Pagination, serialize, compat: bool = False, **meta):
+    """执行分页查询并返回带 skip/limit/total 的列表信封，meta 为额外的元信息，compat 含义同 envelope"""
+    total = query.order_by(None).count()
+    rows = query.offset(page.skip).limit(page.limit).all()
+    return envelope([serialize(r) for r in rows], compat=compat, skip=page.skip, limit=page.limit, total=total, **meta)
@@ -0,0 +1,30 @@
+from typing import Any
+from fastapi.responses import JSONResponse
+from app.config import settings
+
+def to_camel(name: str) -> str:
+    if name.startswith("_") or "_" not in name:
+        return name
+    first, *rest = name.split("_")
+    return first + "".join(p[:1].upper() + p[1:] for p in rest)
+
+def camelize(content: Any) -> Any:
+    if isinstance(content, dict):
+        return {to_camel(k) if isinstance(k, str) else k: camelize(v) for k, v in content.items()}
+    if isinstance(content, list):
+        return [camelize(v) for v in content]
+    return content
+
+def envelope(items: list, compat: bool = False, **meta) -> Any:
+    """列表接口统一返回 {data, meta}；compat 只用于原先返回裸数组的接口，API_ENVELOPE 关闭时仍返回裸数组"""
+    if compat and not settings.API_ENVELOPE:
+        return items
+    return {"data": items, "meta": meta}
+
+class NamedJSONResponse(JSONResponse):
+    """按 JSON_NAMING 配置输出 snake_case 或 camelCase 字段名"""
+
+    def render(self, content: Any) -> bytes:
+        if settings.JSON_NAMING == "camel":
+            content = camelize(content)
+        return super().render(content)
@@ -1,10 +1,27 @@
-from fastapi import APIRouter, Depends, Body
+from typing import Optional
+from fastapi import APIRouter, Depends, Body, Query, HTTPException
+from pydantic import BaseModel, Field
+from sqlalchemy import func
 from sqlalchemy.orm import Session
+from app.ai_models import model_info
+from app.api.auth import require_admin
+from app.api.pagination import Pagination, pagination, paginate
+from app.api.responses import envelope
+from app.config import settings
 from app.database import get_db
 from app.models.ai_analysis import AIAnalysis
+from app.models.analysis_feedback import AnalysisFeedback
+from app.models.repository import Repository
 
 router = APIRouter()
 
+# 粗略估算：平均 4 个字符约为 1 个 token
+CHARS_PER_TOKEN = 4
+
+class FeedbackCreate(BaseModel):
+    rating: int = Field(..., ge=1, le=5)
+    comment: Optional[str] = Field(None, max_length=2000)
+
 @router.post("/analysis/analyze")
 def analyze_project(
     db: Session = Depends(get_db),
@@ -13,10 +30,141 @@ def analyze_project(
     # 这里只做数据库查询，实际AI分析逻辑可后续补充
     analysis = db.query(AIAnalysis).filter(AIAnalysis.url == url).first()
     if analysis:
-        return {"content": analysis.content, "status": analysis.status}
+        return {"content": analysis.content, "status": analysis.status, "model": model_info(analysis.model_version)}
     else:
         return {"content": "暂无分析结果", "status": "pending"}
 
+@router.get("/analysis")
+def get_analysis_by_url(
+    db: Session = Depends(get_db),
+    url: str = Query(..., description="GitHub 仓库地址")
+):
+    analysis = db.query(AIAnalysis).filter(AIAnalysis.url == url.rstrip("/")).first()
+    if not analysis:
+        raise HTTPException(status_code=404, detail="Analysis not found")
+    return {
+        "id": analysis.id,
+        "url": analysis.url,
+        "content": analysis.content,
+        "status": analysis.status,
+        "analysis_type": analysis.analysis_type,
+        "model_version": analysis.model_version,
+        "model": model_info(analysis.model_version),
+        "updated_at": analysis.updated_at,
+    }
+
+@router.post("/analysis/retry-failed", dependencies=[Depends(require_admin)])
+def retry_failed_analyses(db: Session = Depends(get_db)):
+    # 单条 UPDATE，把所有失败的仓库重新放回待分析队列，已软删除的仓库不再分析
+    count = db.query(Repository).filter(
+        Repository.analysis_status == 'failed', Repository.deleted_at.is_(None)
+    ).update({Repository.analysis_status: 'pending'}, synchronize_session=False)
+    db.commit()
+    return {"requeued": count}
+
+@router.post("/analysis/{analysis_id}/feedback")
+def add_analysis_feedback(analysis_id: int, body: FeedbackCreate, db: Session = Depends(get_db)):
+    analysis = db.query(AIAnalysis).filter(AIAnalysis.id == analysis_id).first()
+    if not analysis:
+        raise HTTPException(status_code=404, detail="Analysis not found")
+    db.add(AnalysisFeedback(
+        analysis_id=analysis.id,
+        model_version=analysis.model_version,
+        rating=body.rating,
+        comment=body.comment,
+    ))
+    db.commit()
+    return get_analysis_feedback(analysis_id, db)
+
+@router.get("/analysis/{analysis_id}/feedback")
+def get_analysis_feedback(analysis_id: int, db: Session = Depends(get_db)):
+    count, average = db.query(
+        func.count(AnalysisFeedback.id), func.avg(AnalysisFeedback.rating)
+    ).filter(AnalysisFeedback.analysis_id == analysis_id).one()
+    return {
+        "analysis_id": analysis_id,
+        "count": count,
+        "average_rating": float(average) if average is not None else None,
+    }
+
+@router.get("/analysis/feedback/models")
+def get_feedback_by_model(db: Session = Depends(get_db)):
+    rows = db.query(
+        AnalysisFeedback.model_version,
+        func.count(AnalysisFeedback.id),
+        func.avg(AnalysisFeedback.rating),
+    ).group_by(AnalysisFeedback.model_version).all()
+    return envelope([
+        {
+            "model": model_info(model_version),
+            "count": count,
+            "average_rating": float(average),
+        }
+        for model_version, count, average in rows
+    ], total=len(rows))
+
+@router.get("/analysis/cost-estimate")
+def get_cost_estimate(db: Session = Depends(get_db)):
+    content_length = (
+        func.length(func.coalesce(Repository.readme, '')) +
+        func.length(func.coalesce(Repository.description, ''))
+    )
+    pending, chars = db.query(
+        func.count(Repository.id),
+        func.coalesce(func.sum(content_length), 0),
+    ).filter(Repository.analysis_status == 'pending', Repository.deleted_at.is_(None)).one()
+    content_tokens = int(chars) // CHARS_PER_TOKEN
+    # 用已分析仓库的实际 token 数与内容长度之比校准，没有样本时按 CHARS_PER_TOKEN 估算
+    samples, sample_tokens, sample_chars = db.query(
+        func.count(AIAnalysis.id),
+        func.coalesce(func.sum(AIAnalysis.tokens_used), 0),
+        func.coalesce(func.sum(content_length), 0),
+    ).join(Repository, Repository.url == AIAnalysis.url).filter(
+        AIAnalysis.tokens_used.isnot(None), content_length > 0
+    ).one()
+    tokens_per_char = int(sample_tokens) / int(sample_chars) if sample_chars else None
+    if tokens_per_char is not None:
+        estimated_tokens = int(int(chars) * tokens_per_char)
+    else:
+        estimated_tokens = content_tokens
+    return {
+        "pending_repos": pending,
+        "content_tokens": content_tokens,
+        "calibration_samples": samples,
+        "tokens_per_char": round(tokens_per_char, 6) if tokens_per_char is not None else None,
+        "estimated_tokens": estimated_tokens,
+        "price_per_million_tokens": settings.AI_PRICE_PER_MILLION_TOKENS,
+        "estimated_cost_usd": round(estimated_tokens / 1_000_000 * settings.AI_PRICE_PER_MILLION_TOKENS, 4),
+    }
+
+@router.get("/analyses")
+def list_analyses(
+    db: Session = Depends(get_db),
+    model_version: str = Query(None, description="模型版本"),
+    status: str = Query(None, description="分析状态"),
+    page: Pagination = Depends(pagination)
+):
+    query = db.query(AIAnalysis, Repository.id, Repository.full_name, Repository.stars).outerjoin(
+        Repository, Repository.url == AIAnalysis.url
+    )
+    if model_version:
+        query = query.filter(AIAnalysis.model_version == model_version)
+    if status:
+        query = query.filter(AIAnalysis.status == status)
+    query = query.order_by(func.coalesce(AIAnalysis.updated_at, AIAnalysis.created_at).desc())
+    return paginate(query, page, lambda row: {
+        "id": row[0].id,
+        "url": row[0].url,
+        "repo_id": row[1],
+        "full_name": row[2],
+        "stars": row[3],
+        "content": row[0].content,
+        "status": row[0].status,
+        "model": model_info(row[0].model_version),
+        "tokens_used": row[0].tokens_used,
+        "updated_at": row[0].updated_at,
+    })
+
 @router.get("/analysis/test")
 async def test_analysis():
     return {"msg": "analysis ok"} 
\ No newline at end of file
@@ -0,0 +1,114 @@
+from fastapi import APIRouter, Depends, HTTPException
+from sqlalchemy import func, or_
+from sqlalchemy.orm import Session
+from app.api.responses import envelope
+from app.database import get_db
+from app.models.crawl_history import CrawlHistory
+from app.models.crawl_failure import CrawlFailure
+from app.models.repository import Repository
+
+router = APIRouter()
+
+def repo_brief(repo):
+    return {
+        'id': repo.id,
+        'full_name': repo.full_name,
+        'url': repo.url,
+        'stars': repo.stars,
+        'language': repo.language,
+        'created_at': repo.created_at,
+        'last_crawled_at': repo.last_crawled_at,
+    }
+
+@router.get("/crawls/{crawl_id}")
+def get_crawl_detail(crawl_id: int, db: Session = Depends(get_db)):
+    crawl = db.query(CrawlHistory).filter(CrawlHistory.id == crawl_id).first()
+    if not crawl:
+        raise HTTPException(status_code=404, detail="Crawl not found")
+    failures = db.query(CrawlFailure).filter(
+        CrawlFailure.crawl_history_id == crawl.id
+    ).order_by(CrawlFailure.created_at).all()
+    return {
+        'id': crawl.id,
+        'keyword': crawl.keyword,
+        'status': crawl.status,
+        'started_at': crawl.started_at,
+        'completed_at': crawl.completed_at,
+        'total_repos': crawl.total_repos,
+        'processed_repos': crawl.processed_repos,
+        'inserted_repos': crawl.inserted_repos,
+        'updated_repos': crawl.updated_repos,
+        'skipped_repos': crawl.skipped_repos,
+        'failed_repos': crawl.failed_repos,
+        # 已完成的爬取中既未处理也未记为失败的仓库数，正常应为 0
+        'unaccounted_repos': max((crawl.total_repos or 0) - (crawl.processed_repos or 0) - (crawl.failed_repos or 0), 0) if crawl.completed_at else None,
+        'rate_limit_remaining': crawl.rate_limit_remaining,
+        'duration_seconds': (crawl.completed_at - crawl.started_at).total_seconds() if crawl.completed_at else None,
+        'error_message': crawl.error_message,
+        'failures': [
+            {
+                'full_name': f.full_name,
+                'url': f.url,
+                'error_message': f.error_message,
+                'created_at': f.created_at,
+            }
+            for f in failures
+        ],
+    }
+
+@router.get("/crawls/{crawl_id}/diff")
+def get_crawl_diff(crawl_id: int, db: Session = Depends(get_db)):
+    crawl = db.query(CrawlHistory).filter(CrawlHistory.id == crawl_id).first()
+    if not crawl:
+        raise HTTPException(status_code=404, detail="Crawl not found")
+    new_repos = db.query(Repository).filter(
+        Repository.first_seen_crawl_id == crawl.id,
+        Repository.deleted_at.is_(None),
+    ).order_by(Repository.stars.desc()).all()
+    # 之后的爬取再次写入的仓库 last_crawl_id 会被覆盖，只统计最近一次由本次爬取写入的
+    updated_repos = db.query(Repository).filter(
+        Repository.last_crawl_id == crawl.id,
+        or_(Repository.first_seen_crawl_id.is_(None), Repository.first_seen_crawl_id != crawl.id),
+        Repository.deleted_at.is_(None),
+    ).order_by(Repository.stars.desc()).all()
+
+    # 新增在前、更新在后，change 字段区分两类
+    items = [dict(repo_brief(r), change='new') for r in new_repos]
+    items += [dict(repo_brief(r), change='updated') for r in updated_repos]
+    return envelope(
+        items,
+        crawl_id=crawl.id,
+        keyword=crawl.keyword,
+        started_at=crawl.started_at,
+        completed_at=crawl.completed_at,
+        new=len(new_repos),
+        updated=len(updated_repos),
+    )
+
+@router.get("/keywords/status")
+def get_keywords_status(db: Session = Depends(get_db)):
+    keywords = [k for (k,) in db.query(CrawlHistory.keyword).distinct().order_by(CrawlHistory.keyword).all()]
+    repo_counts = dict(
+        db.query(Repository.search_keyword, func.count(Repository.id))
+        .filter(Repository.search_keyword.in_(keywords), Repository.deleted_at.is_(None))
+        .group_by(Repository.search_keyword)
+        .all()
+    )
+    result = []
+    for keyword in keywords:
+        latest = db.query(CrawlHistory).filter(
+            CrawlHistory.keyword == keyword
+        ).order_by(CrawlHistory.started_at.desc()).first()
+        last_success = db.query(CrawlHistory).filter(
+            CrawlHistory.keyword == keyword,
+            CrawlHistory.status == 'completed',
+        ).order_by(CrawlHistory.completed_at.desc()).first()
+        result.append({
+            'keyword': keyword,
+            'status': latest.status,
+            'last_started_at': latest.started_at,
+            'last_completed_at': last_success.completed_at if last_success else None,
+            'last_total_repos': last_success.total_repos if last_success else None,
+            'repo_count': repo_counts.get(keyword, 0),
+        })
+    return envelope(result, total=len(result))
@@ -0,0 +1,68 @@
+from email.utils import format_datetime
+from xml.sax.saxutils import escape
+from fastapi import APIRouter, Depends, Query, Request
+from fastapi.responses import Response
+from sqlalchemy.orm import Session
+from app.api.pagination import clamp_limit
+from app.api.responses import envelope
+from app.api.routes.repositories import list_query
+from app.config import settings
+from app.database import get_db
+from app.models.ai_analysis import AIAnalysis
+from app.models.repository import Repository
+
+router = APIRouter()
+
+def render_rss(rows, link: str) -> str:
+    items = []
+    for repo, analysis in rows:
+        pub_date = f"<pubDate>{format_datetime(repo.last_analyzed_at)}</pubDate>" if repo.last_analyzed_at else ""
+        items.append(
+            "<item>"
+            f"<title>{escape(repo.full_name)}</title>"
+            f"<link>{escape(repo.url)}</link>"
+            f"<guid isPermaLink=\"false\">{escape(repo.url)}#{analysis.id}</guid>"
+            f"{pub_date}"
+            f"<description>{escape(analysis.content or '')}</description>"
+            "</item>"
+        )
+    return (
+        '<?xml version="1.0" encoding="UTF-8"?>'
+        '<rss version="2.0"><channel>'
+        f"<title>{escape(settings.APP_NAME)} - 最新分析</title>"
+        f"<link>{escape(link)}</link>"
+        "<description>最近完成 AI 分析的 GitHub 项目</description>"
+        + "".join(items) +
+        "</channel></rss>"
+    )
+
+@router.get("/feed")
+def get_feed(
+    request: Request,
+    db: Session = Depends(get_db),
+    limit: int = Query(20, ge=1),
+    format: str = Query("json", pattern="^(json|rss)$", description="输出格式: json/rss")
+):
+    limit = clamp_limit(limit)
+    rows = list_query(db).add_entity(AIAnalysis).join(
+        AIAnalysis, AIAnalysis.url == Repository.url
+    ).filter(
+        Repository.last_analyzed_at.isnot(None), AIAnalysis.content.isnot(None)
+    ).order_by(Repository.last_analyzed_at.desc()).limit(limit).all()
+
+    if format == "rss":
+        # 频道链接指向本服务的 feed 地址；在反向代理之后时需设置 FORWARDED_ALLOW_IPS，uvicorn 才会采用 X-Forwarded-* 中的外部地址
+        return Response(render_rss(rows, str(request.url_for("get_feed"))), media_type="application/rss+xml")
+    return envelope([
+        {
+            'id': repo.id,
+            'full_name': repo.full_name,
+            'url': repo.url,
+            'stars': repo.stars,
+            'language': repo.language,
+            'description': repo.description,
+            'last_analyzed_at': repo.last_analyzed_at,
+            'analysis': {'id': analysis.id, 'content': analysis.content, 'status': analysis.status},
+        }
+        for repo, analysis in rows
+    ], limit=limit)
@@ -0,0 +1,48 @@
+from fastapi import APIRouter, Depends, HTTPException, Query
+from sqlalchemy import func
+from sqlalchemy.orm import Session
+from app.api.pagination import Pagination, pagination, paginate
+from app.api.routes.repositories import list_query, repo_with_analysis
+from app.database import get_db
+from app.models.ai_analysis import AIAnalysis
+from app.models.repository import Repository
+
+router = APIRouter()
+
+@router.get("/owners/{owner}")
+def get_owner_insights(
+    owner: str,
+    db: Session = Depends(get_db),
+    sort: str = Query("stars", description="排序方式: stars/updated"),
+    page: Pagination = Depends(pagination)
+):
+    total_repos, total_stars = db.query(
+        func.count(Repository.id), func.coalesce(func.sum(Repository.stars), 0)
+    ).filter(Repository.owner == owner, Repository.deleted_at.is_(None)).one()
+    if total_repos == 0:
+        raise HTTPException(status_code=404, detail="Owner not found")
+
+    languages = db.query(Repository.language, func.count(Repository.id)).filter(
+        Repository.owner == owner, Repository.deleted_at.is_(None), Repository.language.isnot(None)
+    ).group_by(Repository.language).order_by(func.count(Repository.id).desc()).limit(5).all()
+    analyzed_repos = db.query(func.count(Repository.id)).join(
+        AIAnalysis, AIAnalysis.url == Repository.url
+    ).filter(
+        Repository.owner == owner, Repository.deleted_at.is_(None), AIAnalysis.content.isnot(None)
+    ).scalar()
+
+    query = list_query(db).filter(Repository.owner == owner)
+    if sort == "updated":
+        query = query.order_by(Repository.updated_at.desc())
+    else:
+        query = query.order_by(Repository.stars.desc())
+
+    # 仓库列表放在 data，作者维度的统计放在 meta
+    return paginate(
+        query, page, lambda r: repo_with_analysis(r, db),
+        owner=owner,
+        total_repos=total_repos,
+        total_stars=total_stars,
+        analyzed_repos=analyzed_repos,
+        languages=[{'language': l, 'count': n} for l, n in languages],
+    )
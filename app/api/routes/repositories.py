@@ -1,18 +1,130 @@
-from fastapi import APIRouter, Depends, Query
-from sqlalchemy.orm import Session
-from app.database import get_db
-from app.models.repository import Repository
+from datetime import datetime, timezone
+import json
+import logging
+import re
+import requests
+from fastapi import APIRouter, BackgroundTasks, Depends, Query, HTTPException
+from fastapi.responses import PlainTextResponse
+from pydantic import BaseModel, Field, field_validator
+from sqlalchemy import func
+from sqlalchemy.exc import IntegrityError
+from sqlalchemy.orm import Session, defer
+from app import github
+from app.changes import apply_fields
+from app.derived_topics import extract_topics
+from app.readme_sections import docs_fields
+from app.scores import score_fields
+from app.ai_models import model_info
+from app.api.auth import require_admin
+from app.api.pagination import Pagination, pagination, clamp_limit, paginate
+from app.api.responses import envelope
+from app.database import SessionLocal, get_db
+from app.models.repository import Repository, REPOSITORY_CATEGORIES
 from app.models.ai_analysis import AIAnalysis
+from app.models.ai_analysis_history import AIAnalysisHistory
+from app.models.label import Label
+from app.models.repository_change import RepositoryChange
+
+logger = logging.getLogger("uvicorn.error")
 
 router = APIRouter()
 
+COMPARE_MAX_IDS = 5
+
+# 列表排序方式，空值排在最后
+REPOSITORY_SORTS = {
+    "stars": Repository.stars.desc(),
+    "updated": Repository.updated_at.desc(),
+    "freshness": Repository.freshness_score.desc().nulls_last(),
+    "contributors": Repository.contributor_count.desc().nulls_last(),
+}
+
+# RFC3339 要求带时区，只有日期或不带时区的 ISO 时间都不接受
+RFC3339 = re.compile(r"^\d{4}-\d{2}-\d{2}[Tt ]\d{2}:\d{2}:\d{2}(\.\d{1,6})?([Zz]|[+-]\d{2}:\d{2})$")
+
+class RepositoryCreate(BaseModel):
+    owner: str = Field(..., min_length=1, max_length=100, pattern=r"^[A-Za-z0-9_.-]+$")
+    name: str = Field(..., min_length=1, max_length=100, pattern=r"^[A-Za-z0-9_.-]+$")
+
+LABEL_NAME = re.compile(r"^[A-Za-z0-9 _.:-]+$")
+
+class LabelCreate(BaseModel):
+    name: str = Field(..., max_length=50)
+
+    @field_validator("name")
+    @classmethod
+    def check_name(cls, value: str) -> str:
+        # 先去掉首尾空白再校验，标签名会出现在 URL 路径里，不允许 /
+        value = value.strip()
+        if not value:
+            raise ValueError("label name must not be empty")
+        if not LABEL_NAME.match(value):
+            raise ValueError("label name may only contain letters, digits, spaces and _ . : -")
+        return value
+
+def list_query(db):
+    # 列表接口不加载 readme，需要时通过 /repositories/{id}/readme 获取
+    return db.query(Repository).options(defer(Repository.readme)).filter(Repository.deleted_at.is_(None))
+
+def escape_like(value: str) -> str:
+    # 用户输入按字面匹配，% 和 _ 不作为通配符
+    return value.replace("\\", "\\\\").replace("%", "\\%").replace("_", "\\_")
+
+def contains_item(column, value):
+    # 逗号分隔字段按整项匹配，两端补逗号避免部分匹配
+    return func.concat(',', column, ',').like(f"%,{escape_like(value)},%", escape="\\")
+
+def parse_rfc3339(name: str, value):
+    if value is None:
+        return None
+    try:
+        if not RFC3339.match(value):
+            raise ValueError(value)
+        text = value.upper()
+        return datetime.fromisoformat(text[:-1] + "+00:00" if text.endswith("Z") else text)
+    except ValueError:
+        raise HTTPException(status_code=400, detail=f"{name} must be an RFC3339 timestamp")
+
+def mark_gone(repo):
+    # GitHub 上已删除或转为私有的仓库做软删除，默认列表不再返回
+    repo.deleted_at = datetime.now(timezone.utc)
+    repo.deleted_reason = 'gone'
+
+def mark_live(repo):
+    # GitHub 重新返回了仓库（恢复公开或重新创建），撤销软删除
+    repo.deleted_at = None
+    repo.deleted_reason = None
+
+def find_repository(db, data: dict, requested_full_name: str = None):
+    """按 GitHub 数字 id 匹配已入库的仓库；github_id 尚未回填的旧数据按新旧 full_name 匹配"""
+    repo = db.query(Repository).filter(Repository.github_id == data["id"]).first()
+    if repo:
+        return repo
+    names = {data["full_name"], requested_full_name} - {None}
+    return db.query(Repository).filter(
+        Repository.github_id.is_(None), Repository.full_name.in_(names)
+    ).first()
+
+def move_analysis(db, old_url: str, new_url: str):
+    # 分析结果按 url 关联，仓库改名后 html_url 随之变化，一并迁移以免丢失已有分析
+    if old_url == new_url or db.query(AIAnalysis.id).filter(AIAnalysis.url == new_url).first():
+        return
+    db.query(AIAnalysis).filter(AIAnalysis.url == old_url).update(
+        {AIAnalysis.url: new_url}, synchronize_session=False
+    )
+    db.query(AIAnalysisHistory).filter(AIAnalysisHistory.url == old_url).update(
+        {AIAnalysisHistory.url: new_url}, synchronize_session=False
+    )
+
 def repo_with_analysis(repo, db):
     analysis = db.query(AIAnalysis).filter(AIAnalysis.url == repo.url).first()
     repo_dict = repo.__dict__.copy()
     if analysis:
         repo_dict['analysis'] = {
+            'id': analysis.id,
             'content': analysis.content,
-            'status': analysis.status
+            'status': analysis.status,
+            'model': model_info(analysis.model_version)
         }
     else:
         repo_dict['analysis'] = None
@@ -22,28 +134,315 @@ def repo_with_analysis(repo, db):
 def get_repositories(
     db: Session = Depends(get_db),
     q: str = Query(None, description="搜索关键词"),
-    skip: int = 0,
-    limit: int = 20
+    category: str = Query(None, description="项目分类"),
+    label: str = Query(None, description="用户标签"),
+    maturity: str = Query(None, description="成熟度: experimental/growing/mature/stale"),
+    ecosystem: str = Query(None, description="打包生态: npm/pypi/cargo/go/maven/rubygems/composer"),
+    min_contributors: int = Query(None, ge=0, description="最少贡献者数量"),
+    derived_topic: str = Query(None, description="从 README 提取的主题"),
+    pushed_after: str = Query(None, description="最近推送时间下限 (RFC3339)"),
+    pushed_before: str = Query(None, description="最近推送时间上限 (RFC3339)"),
+    crawled_after: str = Query(None, description="最近爬取时间下限 (RFC3339)"),
+    has_install_docs: bool = Query(None, description="README 包含安装说明"),
+    has_usage_docs: bool = Query(None, description="README 包含使用说明"),
+    has_contributing_docs: bool = Query(None, description="README 包含贡献指南"),
+    has_license_docs: bool = Query(None, description="README 包含许可证说明"),
+    sort: str = Query(None, description="排序方式: stars/updated/freshness/contributors"),
+    page: Pagination = Depends(pagination)
 ):
-    query = db.query(Repository)
+    if sort and sort not in REPOSITORY_SORTS:
+        raise HTTPException(status_code=400, detail=f"sort must be one of {', '.join(REPOSITORY_SORTS)}")
+    pushed_after = parse_rfc3339("pushed_after", pushed_after)
+    pushed_before = parse_rfc3339("pushed_before", pushed_before)
+    crawled_after = parse_rfc3339("crawled_after", crawled_after)
+    if category and category not in REPOSITORY_CATEGORIES:
+        raise HTTPException(status_code=400, detail=f"category must be one of {', '.join(REPOSITORY_CATEGORIES)}")
+    query = list_query(db)
     if q:
         query = query.filter(Repository.full_name.ilike(f"%{q}%"))
-    repos = query.offset(skip).limit(limit).all()
-    return [repo_with_analysis(r, db) for r in repos]
+    if category:
+        query = query.filter(Repository.category == category)
+    if maturity:
+        query = query.filter(Repository.maturity == maturity)
+    if ecosystem:
+        query = query.filter(contains_item(Repository.ecosystem, ecosystem))
+    if derived_topic:
+        query = query.filter(contains_item(Repository.derived_topics, derived_topic.strip().lower()))
+    if min_contributors is not None:
+        query = query.filter(Repository.contributor_count >= min_contributors)
+    if pushed_after:
+        query = query.filter(Repository.last_pushed_at >= pushed_after)
+    if pushed_before:
+        query = query.filter(Repository.last_pushed_at <= pushed_before)
+    if crawled_after:
+        query = query.filter(Repository.last_crawled_at >= crawled_after)
+    if has_install_docs is not None:
+        query = query.filter(Repository.has_install_docs == has_install_docs)
+    if has_usage_docs is not None:
+        query = query.filter(Repository.has_usage_docs == has_usage_docs)
+    if has_contributing_docs is not None:
+        query = query.filter(Repository.has_contributing_docs == has_contributing_docs)
+    if has_license_docs is not None:
+        query = query.filter(Repository.has_license_docs == has_license_docs)
+    if label:
+        query = query.join(Label, Label.repo_id == Repository.id).filter(Label.name == label)
+    if sort:
+        # 追加 id 保证分页顺序稳定
+        query = query.order_by(REPOSITORY_SORTS[sort], Repository.id)
+    return paginate(query, page, lambda r: repo_with_analysis(r, db), compat=True)
+
+@router.post("/repositories")
+def create_repository(body: RepositoryCreate, db: Session = Depends(get_db)):
+    # GitHub 返回 403/5xx 或超时都视为上游故障
+    try:
+        data = github.get_repository(body.owner, body.name)
+        if data is None:
+            raise HTTPException(status_code=404, detail="Repository not found on GitHub")
+        # 改名的仓库 GitHub 返回 301，requests 跟随后 full_name 已是新名字
+        repo = find_repository(db, data, f"{body.owner}/{body.name}")
+        fields = github.fetch_repository_fields(data, repo)
+    except requests.RequestException:
+        logger.exception("GitHub request failed for %s/%s", body.owner, body.name)
+        raise HTTPException(status_code=502, detail="GitHub request failed")
+    if repo:
+        # 已入库的仓库用最新数据刷新，之前被标记为 gone 的一并恢复，并重新排队分析
+        move_analysis(db, repo.url, fields["url"])
+        apply_fields(db, repo, fields)
+        mark_live(repo)
+        repo.analysis_status = 'pending'
+        repo.last_crawled_at = datetime.now(timezone.utc)
+    else:
+        repo = Repository(
+            **fields,
+            search_keyword='manual',
+            analysis_status='pending',
+            last_crawled_at=datetime.now(timezone.utc),
+        )
+        db.add(repo)
+    for key, value in score_fields(repo).items():
+        setattr(repo, key, value)
+    try:
+        db.commit()
+    except IntegrityError:
+        db.rollback()
+        # 原仓库改名后旧名字被另一个仓库使用，需要先对账原仓库
+        raise HTTPException(
+            status_code=409,
+            detail=f"{fields['full_name']} is already tracked as a different GitHub repository",
+        )
+    db.refresh(repo)
+    return repo_with_analysis(repo, db)
+
+def refresh_topics(repo_ids):
+    db = SessionLocal()
+    try:
+        for repo in db.query(Repository).filter(Repository.id.in_(repo_ids)).all():
+            owner, name = repo.full_name.split("/", 1)
+            topics = github.get_topics(owner, name)
+            if topics is not None:
+                repo.topics = ",".join(topics)
+            # 无论结果如何都记录时间，下次刷新轮到其他仓库
+            repo.topics_refreshed_at = datetime.now(timezone.utc)
+            db.commit()
+            if github.rate_limit_low():
+                logger.warning("topics refresh stopped early, GitHub rate limit remaining %d", github.rate_remaining)
+                break
+    finally:
+        db.close()
+
+def reconcile_repositories(repo_ids):
+    db = SessionLocal()
+    try:
+        for repo in db.query(Repository).filter(Repository.id.in_(repo_ids)).all():
+            if repo.github_id:
+                data = github.get_repository_by_id(repo.github_id)
+            else:
+                owner, name = repo.full_name.split("/", 1)
+                data = github.get_repository(owner, name)
+            if data is None:
+                mark_gone(repo)
+            elif repo.github_id is None:
+                # 顺带回填加列之前入库的仓库
+                repo.github_id = data["id"]
+            repo.reconciled_at = datetime.now(timezone.utc)
+            try:
+                db.commit()
+            except IntegrityError:
+                db.rollback()
+                logger.warning("repository %s duplicates GitHub id %s, skipped", repo.full_name, data["id"])
+            if github.rate_limit_low():
+                logger.warning("reconcile stopped early, GitHub rate limit remaining %d", github.rate_remaining)
+                break
+    finally:
+        db.close()
+
+@router.post("/repositories/topics/refresh", status_code=202, dependencies=[Depends(require_admin)])
+def refresh_repository_topics(
+    background_tasks: BackgroundTasks,
+    db: Session = Depends(get_db),
+    limit: int = Query(100, ge=1, le=1000, description="本次刷新的仓库数量")
+):
+    # 最久未刷新 topics 的仓库优先
+    repo_ids = [
+        r.id for r in db.query(Repository.id)
+        .filter(Repository.deleted_at.is_(None))
+        .order_by(Repository.topics_refreshed_at.asc().nulls_first(), Repository.id)
+        .limit(limit).all()
+    ]
+    background_tasks.add_task(refresh_topics, repo_ids)
+    return {"queued": len(repo_ids)}
+
+def refresh_derived_topics(batch_size: int = 200):
+    db = SessionLocal()
+    try:
+        last_id = 0
+        while True:
+            # 按 id 分批处理，每批单独提交
+            repos = db.query(Repository).filter(
+                Repository.id > last_id, Repository.readme.isnot(None)
+            ).order_by(Repository.id).limit(batch_size).all()
+            if not repos:
+                break
+            for repo in repos:
+                repo.derived_topics = ",".join(extract_topics(repo.readme)) or None
+            db.commit()
+            last_id = repos[-1].id
+    finally:
+        db.close()
+
+@router.post("/repositories/derived-topics/refresh", status_code=202, dependencies=[Depends(require_admin)])
+def refresh_repository_derived_topics(background_tasks: BackgroundTasks):
+    # 纯本地计算，不请求 GitHub 或模型
+    background_tasks.add_task(refresh_derived_topics)
+    return {"queued": True}
+
+def refresh_readme_sections(batch_size: int = 200):
+    db = SessionLocal()
+    try:
+        last_id = 0
+        while True:
+            repos = db.query(Repository).filter(
+                Repository.id > last_id
+            ).order_by(Repository.id).limit(batch_size).all()
+            if not repos:
+                break
+            for repo in repos:
+                for key, value in docs_fields(repo.readme).items():
+                    setattr(repo, key, value)
+            db.commit()
+            last_id = repos[-1].id
+    finally:
+        db.close()
+
+@router.post("/repositories/readme-sections/refresh", status_code=202, dependencies=[Depends(require_admin)])
+def refresh_repository_readme_sections(background_tasks: BackgroundTasks):
+    # 纯本地解析 README 标题，不请求 GitHub
+    background_tasks.add_task(refresh_readme_sections)
+    return {"queued": True}
+
+def refresh_scores(batch_size: int = 200):
+    db = SessionLocal()
+    try:
+        now = datetime.now(timezone.utc)
+        last_id = 0
+        while True:
+            repos = db.query(Repository).options(defer(Repository.readme)).filter(
+                Repository.id > last_id
+            ).order_by(Repository.id).limit(batch_size).all()
+            if not repos:
+                break
+            for repo in repos:
+                for key, value in score_fields(repo, now).items():
+                    setattr(repo, key, value)
+            db.commit()
+            last_id = repos[-1].id
+    finally:
+        db.close()
+
+@router.post("/repositories/scores/refresh", status_code=202, dependencies=[Depends(require_admin)])
+def refresh_repository_scores(background_tasks: BackgroundTasks):
+    # 评分随时间衰减，需要定期重算；纯本地计算，不请求 GitHub
+    background_tasks.add_task(refresh_scores)
+    return {"queued": True}
+
+@router.post("/repositories/reconcile", status_code=202, dependencies=[Depends(require_admin)])
+def reconcile_gone_repositories(
+    background_tasks: BackgroundTasks,
+    db: Session = Depends(get_db),
+    limit: int = Query(100, ge=1, le=1000, description="本次检查的仓库数量")
+):
+    repo_ids = [
+        r.id for r in db.query(Repository.id)
+        .filter(Repository.deleted_at.is_(None))
+        .order_by(Repository.reconciled_at.asc().nulls_first(), Repository.id)
+        .limit(limit).all()
+    ]
+    background_tasks.add_task(reconcile_repositories, repo_ids)
+    return {"queued": len(repo_ids)}
+
+@router.get("/repositories/gone")
+def get_gone_repositories(
+    db: Session = Depends(get_db),
+    page: Pagination = Depends(pagination)
+):
+    query = db.query(Repository).options(defer(Repository.readme)).filter(
+        Repository.deleted_reason == 'gone'
+    ).order_by(Repository.deleted_at.desc())
+    return paginate(query, page, lambda r: repo_with_analysis(r, db))
 
 @router.get("/repositories/top")
 def get_top_repositories(
     db: Session = Depends(get_db),
-    sort: str = Query("stars", description="排序方式: stars/updated"),
-    limit: int = 10
+    sort: str = Query("stars", description="排序方式: stars/updated/freshness/contributors"),
+    limit: int = Query(10, ge=1)
 ):
-    if sort == "stars":
-        repos = db.query(Repository).order_by(Repository.stars.desc()).limit(limit).all()
-    elif sort == "updated":
-        repos = db.query(Repository).order_by(Repository.updated_at.desc()).limit(limit).all()
-    else:
-        repos = db.query(Repository).limit(limit).all()
-    return [repo_with_analysis(r, db) for r in repos]
+    limit = clamp_limit(limit)
+    query = list_query(db)
+    if sort in REPOSITORY_SORTS:
+        query = query.order_by(REPOSITORY_SORTS[sort])
+    repos = query.limit(limit).all()
+    return envelope([repo_with_analysis(r, db) for r in repos], compat=True, sort=sort, limit=limit)
+
+@router.get("/repositories/compare")
+def compare_repositories(
+    db: Session = Depends(get_db),
+    ids: str = Query(..., description="逗号分隔的仓库 ID，例如 1,2,3")
+):
+    try:
+        repo_ids = [int(i) for i in ids.split(",") if i.strip()]
+    except ValueError:
+        raise HTTPException(status_code=400, detail="ids must be comma separated integers")
+    if not repo_ids:
+        raise HTTPException(status_code=400, detail="ids is empty")
+    if len(repo_ids) > COMPARE_MAX_IDS:
+        raise HTTPException(status_code=400, detail=f"At most {COMPARE_MAX_IDS} repositories can be compared")
+    repos = {r.id: r for r in list_query(db).filter(Repository.id.in_(repo_ids)).all()}
+    # 按请求中的顺序返回，不存在的 ID 返回 null
+    return envelope([repo_with_analysis(repos[i], db) if i in repos else None for i in repo_ids], ids=repo_ids)
+
+@router.get("/categories")
+def get_categories(db: Session = Depends(get_db)):
+    rows = db.query(Repository.category, func.count(Repository.id)).filter(
+        Repository.deleted_at.is_(None)
+    ).group_by(Repository.category).all()
+    counts = {c: 0 for c in REPOSITORY_CATEGORIES}
+    for c, n in rows:
+        # 数据库触发器已把枚举外的值存为 unknown，这里兜底与 ?category=unknown 的结果保持一致
+        key = c if c in counts else 'unknown'
+        counts[key] += n
+    return envelope([{"category": c, "count": n} for c, n in counts.items()], total=len(counts))
+
+@router.get("/topics/{topic}/repositories")
+def get_topic_repositories(
+    topic: str,
+    db: Session = Depends(get_db),
+    page: Pagination = Depends(pagination)
+):
+    # 路径参数已由框架做 URL 解码，GitHub topics 统一为小写
+    query = list_query(db).filter(
+        contains_item(Repository.topics, topic.strip().lower())
+    ).order_by(Repository.stars.desc())
+    return paginate(query, page, lambda r: repo_with_analysis(r, db))
 
 @router.get("/repositories/{repo_id}")
 def get_repository_detail(repo_id: int, db: Session = Depends(get_db)):
@@ -52,6 +451,110 @@ def get_repository_detail(repo_id: int, db: Session = Depends(get_db)):
         return {"error": "Not found"}
     return repo_with_analysis(repo, db)
 
+@router.get("/repositories/{repo_id}/readme", response_class=PlainTextResponse)
+def get_repository_readme(repo_id: int, db: Session = Depends(get_db)):
+    repo = db.query(Repository).filter(Repository.id == repo_id).first()
+    if not repo:
+        raise HTTPException(status_code=404, detail="Repository not found")
+    return PlainTextResponse(repo.readme or "", media_type="text/markdown")
+
+def render_summary(repo, analysis, markdown: bool) -> str:
+    stats = [
+        ("Stars", repo.stars),
+        ("Language", repo.language or "-"),
+        ("License", repo.license or "-"),
+    ]
+    if repo.homepage:
+        stats.append(("Homepage", repo.homepage))
+    content = analysis.content if analysis and analysis.content else "暂无分析结果"
+    if markdown:
+        lines = [f"# [{repo.full_name}]({repo.url})", ""]
+        if repo.description:
+            lines += [f"> {repo.description}", ""]
+        lines += [f"- **{k}:** {v}" for k, v in stats]
+        lines += ["", "## AI 分析", "", content]
+    else:
+        lines = [repo.full_name, repo.url, ""]
+        if repo.description:
+            lines += [repo.description, ""]
+        lines += [f"{k}: {v}" for k, v in stats]
+        lines += ["", "AI 分析:", content]
+    return "\n".join(lines) + "\n"
+
+@router.get("/repositories/{repo_id}/summary", response_class=PlainTextResponse)
+def get_repository_summary(
+    repo_id: int,
+    db: Session = Depends(get_db),
+    format: str = Query("markdown", pattern="^(markdown|text)$", description="输出格式: markdown/text")
+):
+    repo = db.query(Repository).filter(Repository.id == repo_id).first()
+    if not repo:
+        raise HTTPException(status_code=404, detail="Repository not found")
+    analysis = db.query(AIAnalysis).filter(AIAnalysis.url == repo.url).first()
+    markdown = format == "markdown"
+    return PlainTextResponse(
+        render_summary(repo, analysis, markdown),
+        media_type="text/markdown" if markdown else "text/plain",
+    )
+
+@router.get("/repositories/{repo_id}/analysis/history")
+def get_repository_analysis_history(repo_id: int, db: Session = Depends(get_db)):
+    repo = db.query(Repository).filter(Repository.id == repo_id).first()
+    if not repo:
+        raise HTTPException(status_code=404, detail="Repository not found")
+    versions = (
+        db.query(AIAnalysisHistory)
+        .filter(AIAnalysisHistory.url == repo.url)
+        .order_by(AIAnalysisHistory.created_at.desc())
+        .all()
+    )
+    return envelope([
+        {
+            'id': v.id,
+            'created_at': v.created_at,
+            'content': v.content,
+            'status': v.status,
+            'model_version': v.model_version,
+            'model': model_info(v.model_version),
+            'tokens_used': v.tokens_used,
+        }
+        for v in versions
+    ], repo_id=repo.id, total=len(versions))
+
+@router.get("/repositories/{repo_id}/changes")
+def get_repository_changes(
+    repo_id: int,
+    db: Session = Depends(get_db),
+    page: Pagination = Depends(pagination)
+):
+    query = db.query(RepositoryChange).filter(
+        RepositoryChange.repo_id == repo_id
+    ).order_by(RepositoryChange.created_at.desc())
+    return paginate(query, page, lambda r: {'created_at': r.created_at, 'changes': json.loads(r.changes)})
+
+@router.get("/repositories/{repo_id}/labels")
+def get_repository_labels(repo_id: int, db: Session = Depends(get_db)):
+    labels = db.query(Label).filter(Label.repo_id == repo_id).order_by(Label.name).all()
+    return envelope([l.name for l in labels], repo_id=repo_id, total=len(labels))
+
+@router.post("/repositories/{repo_id}/labels")
+def add_repository_label(repo_id: int, body: LabelCreate, db: Session = Depends(get_db)):
+    repo = db.query(Repository).filter(Repository.id == repo_id).first()
+    if not repo:
+        raise HTTPException(status_code=404, detail="Repository not found")
+    name = body.name
+    exists = db.query(Label).filter(Label.repo_id == repo_id, Label.name == name).first()
+    if not exists:
+        db.add(Label(repo_id=repo_id, name=name))
+        db.commit()
+    return get_repository_labels(repo_id, db)
+
+@router.delete("/repositories/{repo_id}/labels/{name}")
+def remove_repository_label(repo_id: int, name: str, db: Session = Depends(get_db)):
+    db.query(Label).filter(Label.repo_id == repo_id, Label.name == name).delete()
+    db.commit()
+    return get_repository_labels(repo_id, db)
+
 @router.get("/repositories/test")
 async def test_repo():
     return {"msg": "repositories ok"} 
\ No newline at end of file
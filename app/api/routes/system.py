@@ -0,0 +1,36 @@
+import requests
+from fastapi import APIRouter, Depends, HTTPException, Query
+from pydantic import BaseModel, Field
+from app import github
+from app.api.auth import require_admin
+from app.config import settings
+
+router = APIRouter(dependencies=[Depends(require_admin)])
+
+class RateLimitUpdate(BaseModel):
+    # 0 表示不限速
+    requests_per_second: float = Field(..., ge=0)
+
+def rate_limit_status():
+    return {
+        "requests_per_second": github.limiter.rate,
+        "remaining": github.rate_remaining,
+        "reset_at": github.rate_reset_at,
+        "reserve": settings.GITHUB_RATE_LIMIT_RESERVE,
+    }
+
+@router.get("/system/ratelimit")
+def get_rate_limit(refresh: bool = Query(False, description="向 GitHub 查询最新剩余额度")):
+    # 服务启动后还没有请求过 GitHub 时也主动查询一次
+    if refresh or github.rate_remaining is None:
+        try:
+            github.refresh_rate_limit()
+        except requests.RequestException:
+            raise HTTPException(status_code=502, detail="GitHub request failed")
+    return rate_limit_status()
+
+@router.put("/system/ratelimit")
+def update_rate_limit(body: RateLimitUpdate):
+    # 只调整当前进程，重启后恢复为 GITHUB_REQUESTS_PER_SECOND
+    github.limiter.set_rate(body.requests_per_second)
+    return rate_limit_status()
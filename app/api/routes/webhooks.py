@@ -0,0 +1,118 @@
+import hashlib
+import hmac
+import json
+import logging
+from datetime import datetime, timezone
+from urllib.parse import parse_qs
+from fastapi import APIRouter, BackgroundTasks, Header, HTTPException, Request
+from fastapi.concurrency import run_in_threadpool
+from sqlalchemy.exc import IntegrityError
+from app import github
+from app.api.routes.repositories import mark_gone, mark_live, move_analysis
+from app.changes import apply_fields
+from app.config import settings
+from app.database import SessionLocal
+from app.scores import score_fields
+from app.models.repository import Repository
+
+logger = logging.getLogger("uvicorn.error")
+
+router = APIRouter()
+
+# 触发重新爬取的事件，watch 即 star
+RECRAWL_EVENTS = ("push", "star", "watch")
+
+def verify_signature(body: bytes, signature: str) -> bool:
+    if not settings.GITHUB_WEBHOOK_SECRET or not signature:
+        return False
+    expected = "sha256=" + hmac.new(
+        settings.GITHUB_WEBHOOK_SECRET.encode(), body, hashlib.sha256
+    ).hexdigest()
+    return hmac.compare_digest(expected, signature)
+
+def parse_payload(body: bytes, content_type: str) -> dict:
+    """解析 webhook 负载，兼容 GitHub 的 application/json 和 application/x-www-form-urlencoded 两种格式"""
+    if content_type.startswith("application/x-www-form-urlencoded"):
+        payload = parse_qs(body.decode()).get("payload")
+        if not payload:
+            raise ValueError("missing payload field")
+        body = payload[0]
+    data = json.loads(body)
+    if not isinstance(data, dict):
+        raise ValueError("payload is not an object")
+    return data
+
+def find_repository_id(github_id, full_name: str):
+    db = SessionLocal()
+    try:
+        repo = None
+        if github_id is not None:
+            repo = db.query(Repository.id).filter(Repository.github_id == github_id).first()
+        if repo is None:
+            repo = db.query(Repository.id).filter(Repository.full_name == full_name).first()
+        return repo.id if repo else None
+    finally:
+        db.close()
+
+def recrawl_repository(repo_id: int):
+    db = SessionLocal()
+    try:
+        repo = db.query(Repository).filter(Repository.id == repo_id).first()
+        if not repo:
+            return
+        # 有 github_id 时按 id 获取，改名后旧名字被其他仓库占用也不会取错
+        if repo.github_id:
+            data = github.get_repository_by_id(repo.github_id)
+        else:
+            owner, name = repo.full_name.split("/", 1)
+            data = github.get_repository(owner, name)
+        if data is None:
+            mark_gone(repo)
+            db.commit()
+            return
+        fields = github.fetch_repository_fields(data, repo)
+        move_analysis(db, repo.url, fields["url"])
+        apply_fields(db, repo, fields)
+        for key, value in score_fields(repo).items():
+            setattr(repo, key, value)
+        mark_live(repo)
+        repo.last_crawled_at = datetime.now(timezone.utc)
+        repo.analysis_status = 'pending'
+        try:
+            db.commit()
+        except IntegrityError:
+            db.rollback()
+            # 改名后的 full_name 已被另一条记录占用，等对账处理后再更新
+            logger.warning("recrawl of repository %d skipped, %s is already used by another row", repo_id, fields["full_name"])
+    finally:
+        db.close()
+
+@router.post("/webhooks/github", status_code=202)
+async def github_webhook(
+    request: Request,
+    background_tasks: BackgroundTasks,
+    x_github_event: str = Header(None),
+    x_hub_signature_256: str = Header(None),
+):
+    body = await request.body()
+    if not verify_signature(body, x_hub_signature_256):
+        raise HTTPException(status_code=401, detail="Invalid signature")
+    if x_github_event == "ping":
+        return {"msg": "pong"}
+    if x_github_event not in RECRAWL_EVENTS:
+        return {"queued": False}
+
+    try:
+        payload = parse_payload(body, request.headers.get("content-type", ""))
+    except (UnicodeDecodeError, ValueError):
+        raise HTTPException(status_code=400, detail="Invalid payload")
+    repository = payload.get("repository") or {}
+    full_name = repository.get("full_name")
+    if not full_name:
+        raise HTTPException(status_code=400, detail="Missing repository")
+    # 同步的数据库查询放到线程池，避免阻塞事件循环
+    repo_id = await run_in_threadpool(find_repository_id, repository.get("id"), full_name)
+    if repo_id is None:
+        return {"queued": False}
+    background_tasks.add_task(recrawl_repository, repo_id)
+    return {"queued": True, "full_name": full_name}
@@ -0,0 +1,28 @@
+import json
+import logging
+from .config import settings
+from .models.repository_change import RepositoryChange
+
+logger = logging.getLogger("uvicorn.error")
+
+# 只记录时间戳变化或回填没有意义的字段
+IGNORED_CHANGE_FIELDS = ("contributors_updated_at", "github_id")
+
+def diff_fields(repo, fields: dict) -> dict:
+    changes = {}
+    for key, value in fields.items():
+        old = getattr(repo, key)
+        if key not in IGNORED_CHANGE_FIELDS and old != value:
+            changes[key] = {"old": old, "new": value}
+    return changes
+
+def apply_fields(db, repo, fields: dict) -> dict:
+    """把最新字段写入已入库的仓库，有变化时记录日志，并按 RECORD_REPOSITORY_CHANGES 写入 repository_change"""
+    changes = diff_fields(repo, fields)
+    if changes:
+        logger.info("repository %s changed: %s", repo.full_name, ", ".join(changes))
+        if settings.RECORD_REPOSITORY_CHANGES:
+            db.add(RepositoryChange(repo_id=repo.id, changes=json.dumps(changes, default=str)))
+    for key, value in fields.items():
+        setattr(repo, key, value)
+    return changes
@@ -1,5 +1,7 @@
+import os
+from pydantic import Field, model_validator
 from pydantic_settings import BaseSettings
-from typing import Optional
+from typing import Literal, Optional
 
 class Settings(BaseSettings):
     # 数据库配置
@@ -8,19 +10,101 @@ class Settings(BaseSettings):
     DB_USER: str = "postgres"
     DB_PASSWORD: str = "postgres"
     DB_NAME: str = "repoinsight"
+    # 默认 prefer 与 libpq 一致；托管数据库（RDS、Cloud SQL）通常需要 require 或 verify-full
+    DB_SSLMODE: Literal["disable", "allow", "prefer", "require", "verify-ca", "verify-full"] = "prefer"
+    DB_SSLROOTCERT: Optional[str] = None
+    # 连接池配置，默认值与 SQLAlchemy 默认一致；最大连接数为 DB_POOL_SIZE + DB_MAX_OVERFLOW
+    DB_POOL_SIZE: int = Field(5, ge=1)
+    DB_MAX_OVERFLOW: int = Field(10, ge=0)
+    DB_POOL_TIMEOUT: int = Field(30, ge=1)
+    DB_POOL_RECYCLE: int = -1
+    # 单条 SQL 最长执行时间（毫秒），0 表示不限制
+    DB_STATEMENT_TIMEOUT: int = Field(30000, ge=0)
 
     # GitHub配置
     GITHUB_TOKEN: str
+    # 未设置时拒绝所有 webhook 请求
+    GITHUB_WEBHOOK_SECRET: Optional[str] = None
+    GITHUB_TIMEOUT: float = 10
+    GITHUB_POOL_CONNECTIONS: int = Field(10, ge=1)
+    GITHUB_POOL_MAXSIZE: int = Field(10, ge=1)
+    # 获取仓库时额外请求最新 release，每个仓库多一次 API 调用
+    GITHUB_FETCH_RELEASES: bool = False
+    # 获取仓库时额外读取根目录识别打包生态，每个仓库多一次 API 调用
+    GITHUB_DETECT_ECOSYSTEM: bool = False
+    # 获取贡献者数量，结果在 GITHUB_CONTRIBUTORS_REFRESH_HOURS 内不重复请求
+    GITHUB_FETCH_CONTRIBUTORS: bool = False
+    GITHUB_CONTRIBUTORS_REFRESH_HOURS: int = Field(168, ge=0)
+    # 重新获取仓库时把有变化的字段写入 repository_change
+    RECORD_REPOSITORY_CHANGES: bool = True
+    # 每秒最多发出的 GitHub 请求数，0 表示不限速
+    GITHUB_REQUESTS_PER_SECOND: float = Field(5, ge=0)
+    # 剩余请求额度低于该值时停止批量任务，给爬虫留出余量
+    GITHUB_RATE_LIMIT_RESERVE: int = Field(100, ge=0)
+
+    # 活跃度评分，公式见 app/scores.py；权重之和建议为 1
+    FRESHNESS_PUSH_WEIGHT: float = Field(0.7, ge=0)
+    FRESHNESS_STARS_WEIGHT: float = Field(0.3, ge=0)
+    FRESHNESS_ARCHIVED_PENALTY: float = Field(0.5, ge=0)
+    # 最近推送的时效分每隔多少天减半
+    FRESHNESS_HALF_LIFE_DAYS: float = Field(90, gt=0)
+
+    # 成熟度分类阈值，判断顺序见 app/scores.py
+    MATURITY_STALE_DAYS: int = Field(365, ge=1)
+    MATURITY_EXPERIMENTAL_MAX_AGE_DAYS: int = Field(180, ge=0)
+    MATURITY_GROWING_MIN_STARS: int = Field(100, ge=0)
+    MATURITY_MATURE_MIN_AGE_DAYS: int = Field(730, ge=0)
+    MATURITY_MATURE_MIN_STARS: int = Field(1000, ge=0)
 
     # DeepSeek AI配置
     DEEPSEEK_API_KEY: str
+    # 费用估算使用的每百万 token 单价（美元，输入输出混合）
+    AI_PRICE_PER_MILLION_TOKENS: float = Field(1.0, ge=0)
+
+    # 管理类接口（重试、刷新、对账）使用的 Bearer token，未设置时拒绝所有管理请求
+    ADMIN_TOKEN: Optional[str] = None
 
     # 应用配置
     APP_NAME: str = "RepoInsight"
     DEBUG: bool = False
     API_PREFIX: str = "/api/v1"
+    HOST: str = "0.0.0.0"
+    PORT: int = 8000
+    # 代码变更时自动重启，仅用于开发
+    RELOAD: bool = False
+    # 优雅关闭时等待请求处理完成的秒数，未设置时一直等待
+    SHUTDOWN_TIMEOUT: Optional[int] = None
+    ENABLE_GZIP: bool = True
+    GZIP_MINIMUM_SIZE: int = 1000
+    # 列表接口返回 {data, meta}；设为 False 时 /repositories 和 /repositories/top 返回裸数组，兼容旧客户端，其余接口不受影响
+    API_ENVELOPE: bool = True
+    # API 响应字段命名风格: snake/camel
+    JSON_NAMING: Literal["snake", "camel"] = "snake"
+    DEFAULT_PAGE_SIZE: int = Field(20, ge=1)
+    MAX_PAGE_SIZE: int = Field(100, ge=1)
+
+    @model_validator(mode="after")
+    def check_page_size(self):
+        # 默认值超过上限会绕过 MAX_PAGE_SIZE 的限制
+        if self.DEFAULT_PAGE_SIZE > self.MAX_PAGE_SIZE:
+            raise ValueError("DEFAULT_PAGE_SIZE must not exceed MAX_PAGE_SIZE")
+        return self
+
+    def redacted(self) -> dict:
+        """返回生效配置，密钥类字段替换为 ***"""
+        # 按后缀匹配，避免 AI_PRICE_PER_MILLION_TOKENS 这类普通配置被误判
+        secret_suffixes = ("_TOKEN", "_KEY", "_PASSWORD", "_SECRET")
+        return {
+            name: "***" if value and name.endswith(secret_suffixes) else value
+            for name, value in self.model_dump().items()
+        }
 
     class Config:
         env_file = ".env"
 
-settings = Settings() 
\ No newline at end of file
+def load_settings(path: Optional[str] = None) -> Settings:
+    """从 path 指定的 .env 文件加载配置，未指定时读取 CONFIG_PATH，默认当前目录下的 .env；环境变量优先于文件"""
+    return Settings(_env_file=path or os.getenv("CONFIG_PATH", ".env"))
+
+# CONFIG_PATH 指定 .env 文件位置，便于容器和 systemd 部署
+settings = load_settings() 
\ No newline at end of file
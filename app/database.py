@@ -5,7 +5,22 @@ from .config import settings
 
 SQLALCHEMY_DATABASE_URL = f"postgresql://{settings.DB_USER}:{settings.DB_PASSWORD}@{settings.DB_HOST}:{settings.DB_PORT}/{settings.DB_NAME}"
 
-engine = create_engine(SQLALCHEMY_DATABASE_URL)
+connect_args = {
+    "sslmode": settings.DB_SSLMODE,
+    # 每个连接建立时设置 statement_timeout
+    "options": f"-c statement_timeout={settings.DB_STATEMENT_TIMEOUT}",
+}
+if settings.DB_SSLROOTCERT:
+    connect_args["sslrootcert"] = settings.DB_SSLROOTCERT
+
+engine = create_engine(
+    SQLALCHEMY_DATABASE_URL,
+    connect_args=connect_args,
+    pool_size=settings.DB_POOL_SIZE,
+    max_overflow=settings.DB_MAX_OVERFLOW,
+    pool_timeout=settings.DB_POOL_TIMEOUT,
+    pool_recycle=settings.DB_POOL_RECYCLE,
+)
 SessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=engine)
 
 Base = declarative_base()
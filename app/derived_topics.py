@@ -0,0 +1,34 @@
+import re
+from collections import Counter
+
+# 常见英文停用词和 README 中的通用词，不作为主题
+STOPWORDS = frozenset("""
+a about above after again all also an and any are as at be because been before being below between both
+but by can could did do does doing down during each few for from further had has have having here how
+if in into is it its itself just more most no nor not now of off on once only or other our out over own
+same should so some such than that the their them then there these they this those through to too under
+until up very was we were what when where which while who why will with would you your yours
+able add added adds based build built code copy default docs documentation download example examples
+file files first following get github here http https install installation license like make md must
+need new note npm one open please project readme release run running see set setup simple start support
+two usage use used uses using version via want way well work works www
+""".split())
+
+CODE_BLOCK = re.compile(r"```.*?```", re.S)
+INLINE_NOISE = re.compile(r"`[^`]*`|https?://\S+|<[^>]+>|!\[[^\]]*\]\([^)]*\)")
+WORD = re.compile(r"[a-z][a-z0-9+#-]{2,29}")
+
+def extract_topics(readme: str, limit: int = 10, min_count: int = 2) -> list:
+    """按词频从 README 提取主题词，去掉代码块、链接和停用词，结果确定且可重复"""
+    if not readme:
+        return []
+    text = INLINE_NOISE.sub(" ", CODE_BLOCK.sub(" ", readme)).lower()
+    # 去掉首尾连字符后重新检查长度，避免 -ab- 这类词变成两个字母的主题
+    words = (w.strip("-") for w in WORD.findall(text))
+    counts = Counter(
+        w for w in words
+        if len(w) >= 3 and w not in STOPWORDS and not w.isdigit()
+    )
+    # 同频按字母排序，保证结果稳定
+    ranked = sorted((item for item in counts.items() if item[1] >= min_count), key=lambda x: (-x[1], x[0]))
+    return [w for w, _ in ranked[:limit]]
@@ -0,0 +1,210 @@
+import threading
+import time
+from datetime import datetime, timedelta, timezone
+from urllib.parse import parse_qs, urlparse
+import requests
+from requests.adapters import HTTPAdapter
+from .config import settings
+
+GITHUB_API_URL = "https://api.github.com"
+
+# 复用连接，连接池大小可通过配置调整
+session = requests.Session()
+session.mount("https://", HTTPAdapter(
+    pool_connections=settings.GITHUB_POOL_CONNECTIONS,
+    pool_maxsize=settings.GITHUB_POOL_MAXSIZE,
+))
+
+# 最近一次请求响应头中的剩余请求额度和额度重置时间
+rate_remaining = None
+rate_reset_at = None
+
+class TokenBucket:
+    """线程安全的令牌桶，rate 为每秒补充的令牌数，桶容量为 max(rate, 1)；rate 为 0 时不限速"""
+
+    def __init__(self, rate: float):
+        self._lock = threading.Lock()
+        self.set_rate(rate)
+
+    def set_rate(self, rate: float):
+        with self._lock:
+            self.rate = rate
+            self.capacity = max(rate, 1)
+            self._tokens = self.capacity
+            self._updated = time.monotonic()
+
+    def acquire(self):
+        """取一个令牌，没有可用令牌时阻塞等待"""
+        while True:
+            with self._lock:
+                if self.rate <= 0:
+                    return
+                now = time.monotonic()
+                self._tokens = min(self.capacity, self._tokens + (now - self._updated) * self.rate)
+                self._updated = now
+                if self._tokens >= 1:
+                    self._tokens -= 1
+                    return
+                wait = (1 - self._tokens) / self.rate
+            time.sleep(wait)
+
+# 所有 GitHub 请求共用，批量任务和接口请求一起受限
+limiter = TokenBucket(settings.GITHUB_REQUESTS_PER_SECOND)
+
+def _headers():
+    headers = {"Accept": "application/vnd.github+json"}
+    if settings.GITHUB_TOKEN:
+        headers["Authorization"] = f"Bearer {settings.GITHUB_TOKEN}"
+    return headers
+
+def _parse_time(value):
+    if not value:
+        return None
+    return datetime.fromisoformat(value.replace("Z", "+00:00"))
+
+def _get(path: str):
+    global rate_remaining, rate_reset_at
+    limiter.acquire()
+    resp = session.get(f"{GITHUB_API_URL}{path}", headers=_headers(), timeout=settings.GITHUB_TIMEOUT)
+    remaining = resp.headers.get("X-RateLimit-Remaining")
+    if remaining is not None:
+        rate_remaining = int(remaining)
+    reset = resp.headers.get("X-RateLimit-Reset")
+    if reset is not None:
+        rate_reset_at = datetime.fromtimestamp(int(reset), timezone.utc)
+    return resp
+
+def rate_limit_low() -> bool:
+    """最近一次请求返回的剩余额度是否已低于 GITHUB_RATE_LIMIT_RESERVE"""
+    return rate_remaining is not None and rate_remaining <= settings.GITHUB_RATE_LIMIT_RESERVE
+
+def refresh_rate_limit():
+    """请求 /rate_limit 更新剩余额度，该接口本身不消耗额度"""
+    _get("/rate_limit").raise_for_status()
+
+def get_repository(owner: str, name: str):
+    """获取仓库信息，仓库不存在时返回 None"""
+    resp = _get(f"/repos/{owner}/{name}")
+    if resp.status_code == 404:
+        return None
+    resp.raise_for_status()
+    return resp.json()
+
+def get_repository_by_id(github_id: int):
+    """按 GitHub 数字 id 获取仓库，改名后仍能找到，仓库不存在时返回 None"""
+    resp = _get(f"/repositories/{github_id}")
+    if resp.status_code == 404:
+        return None
+    resp.raise_for_status()
+    return resp.json()
+
+def get_topics(owner: str, name: str):
+    """获取仓库 topics，仓库不存在时返回 None"""
+    resp = _get(f"/repos/{owner}/{name}/topics")
+    if resp.status_code == 404:
+        return None
+    resp.raise_for_status()
+    return resp.json().get("names", [])
+
+def release_fields(full_name: str) -> dict:
+    """获取最新 release 对应的 Repository 字段，未开启 GITHUB_FETCH_RELEASES 时返回空字典"""
+    if not settings.GITHUB_FETCH_RELEASES:
+        return {}
+    resp = _get(f"/repos/{full_name}/releases/latest")
+    # 没有发布过 release 的仓库返回 404
+    if resp.status_code == 404:
+        return {"latest_release_tag": None, "latest_release_at": None}
+    resp.raise_for_status()
+    data = resp.json()
+    return {
+        "latest_release_tag": data.get("tag_name"),
+        "latest_release_at": _parse_time(data.get("published_at")),
+    }
+
+# 根目录清单文件到打包生态的映射
+ECOSYSTEM_MANIFESTS = {
+    "package.json": "npm",
+    "pyproject.toml": "pypi",
+    "setup.py": "pypi",
+    "Cargo.toml": "cargo",
+    "go.mod": "go",
+    "pom.xml": "maven",
+    "build.gradle": "maven",
+    "Gemfile": "rubygems",
+    "composer.json": "composer",
+}
+
+def ecosystem_fields(full_name: str) -> dict:
+    """根据根目录清单文件识别打包生态，多个生态用逗号分隔，未开启 GITHUB_DETECT_ECOSYSTEM 时返回空字典"""
+    if not settings.GITHUB_DETECT_ECOSYSTEM:
+        return {}
+    resp = _get(f"/repos/{full_name}/contents/")
+    # 空仓库没有内容
+    if resp.status_code == 404:
+        return {"ecosystem": None}
+    resp.raise_for_status()
+    names = {item.get("name") for item in resp.json() if item.get("type") == "file"}
+    ecosystems = sorted({eco for manifest, eco in ECOSYSTEM_MANIFESTS.items() if manifest in names})
+    return {"ecosystem": ",".join(ecosystems) or None}
+
+def contributor_fields(full_name: str, repo=None) -> dict:
+    """获取贡献者数量，未开启 GITHUB_FETCH_CONTRIBUTORS 或缓存未过期时返回空字典"""
+    if not settings.GITHUB_FETCH_CONTRIBUTORS:
+        return {}
+    now = datetime.now(timezone.utc)
+    refresh_after = timedelta(hours=settings.GITHUB_CONTRIBUTORS_REFRESH_HOURS)
+    if repo is not None and repo.contributors_updated_at and now - repo.contributors_updated_at < refresh_after:
+        return {}
+    # 每页 1 条，Link 头中 last 页码即为贡献者总数
+    resp = _get(f"/repos/{full_name}/contributors?per_page=1&anon=true")
+    # 贡献者过多的仓库 GitHub 返回 403，数量未知时不更新字段
+    if resp.status_code == 403:
+        return {}
+    if resp.status_code == 204:
+        count = 0
+    else:
+        resp.raise_for_status()
+        last = resp.links.get("last", {}).get("url")
+        count = int(parse_qs(urlparse(last).query)["page"][0]) if last else len(resp.json())
+    return {"contributor_count": count, "contributors_updated_at": now}
+
+def repository_fields(data: dict) -> dict:
+    """把 GitHub API 返回的仓库数据转换为 Repository 字段"""
+    license_info = data.get("license") or {}
+    return {
+        "github_id": data["id"],
+        "full_name": data["full_name"],
+        "name": data["name"],
+        "owner": data["owner"]["login"],
+        "owner_avatar_url": data["owner"].get("avatar_url"),
+        "owner_type": data["owner"].get("type"),
+        "description": data.get("description"),
+        "url": data["html_url"],
+        "homepage": data.get("homepage") or None,
+        "stars": data.get("stargazers_count", 0),
+        "forks": data.get("forks_count", 0),
+        "language": data.get("language"),
+        "topics": ",".join(data.get("topics") or []),
+        "github_created_at": _parse_time(data.get("created_at")),
+        "last_pushed_at": _parse_time(data.get("pushed_at")),
+        "is_archived": data.get("archived", False),
+        "license": license_info.get("spdx_id"),
+        "default_branch": data.get("default_branch"),
+        "open_issues": data.get("open_issues_count", 0),
+        "watchers": data.get("watchers_count", 0),
+        "size": data.get("size", 0),
+        "has_issues": data.get("has_issues", True),
+        "has_projects": data.get("has_projects", True),
+        "has_wiki": data.get("has_wiki", True),
+        "has_pages": data.get("has_pages", False),
+        "has_downloads": data.get("has_downloads", True),
+        "is_template": data.get("is_template", False),
+    }
+
+def fetch_repository_fields(data: dict, repo=None) -> dict:
+    """仓库字段加上按配置额外获取的 release、打包生态和贡献者信息，repo 为已入库的记录"""
+    fields = repository_fields(data)
+    fields.update(release_fields(fields["full_name"]))
+    fields.update(ecosystem_fields(fields["full_name"]))
+    fields.update(contributor_fields(fields["full_name"], repo))
+    return fields
@@ -1,13 +1,51 @@
-from fastapi import FastAPI
+import argparse
+import logging
+import os
+from contextlib import asynccontextmanager
+from fastapi import FastAPI, Request
+from fastapi.encoders import jsonable_encoder
+from fastapi.exception_handlers import request_validation_exception_handler
+from fastapi.exceptions import RequestValidationError
 from fastapi.middleware.cors import CORSMiddleware
+from fastapi.middleware.gzip import GZipMiddleware
+
+def parse_args(argv=None):
+    parser = argparse.ArgumentParser(description="RepoInsight API")
+    parser.add_argument("-config", "--config", dest="config", help="配置文件路径，优先于环境变量 CONFIG_PATH")
+    return parser.parse_args(argv)
+
+# settings 在导入 config 时加载，命令行指定的路径要在导入前写入 CONFIG_PATH
+if __name__ == "__main__":
+    config_path = parse_args().config
+    if config_path:
+        os.environ["CONFIG_PATH"] = config_path
+
 from .config import settings
-from .api.routes import repositories, analysis
+from .api.routes import repositories, analysis, crawls, owners, webhooks, feed, system
+from .api import graphql
+from .api.responses import NamedJSONResponse
+
+logger = logging.getLogger("uvicorn.error")
+
+@asynccontextmanager
+async def lifespan(app: FastAPI):
+    logger.info("%s starting with config: %s", settings.APP_NAME, settings.redacted())
+    yield
 
 app = FastAPI(
     title=settings.APP_NAME,
-    debug=settings.DEBUG
+    debug=settings.DEBUG,
+    lifespan=lifespan,
+    default_response_class=NamedJSONResponse
 )
 
+# 手动添加仓库的参数校验失败返回 400，其余接口保持 FastAPI 默认的 422
+@app.exception_handler(RequestValidationError)
+async def validation_exception_handler(request: Request, exc: RequestValidationError):
+    if request.scope.get("endpoint") is repositories.create_repository:
+        return NamedJSONResponse(status_code=400, content={"detail": jsonable_encoder(exc.errors())})
+    return await request_validation_exception_handler(request, exc)
+
 # 配置CORS
 app.add_middleware(
     CORSMiddleware,
@@ -17,10 +55,33 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
+# 按 Accept-Encoding 压缩响应，小于 GZIP_MINIMUM_SIZE 字节的响应不压缩
+if settings.ENABLE_GZIP:
+    app.add_middleware(GZipMiddleware, minimum_size=settings.GZIP_MINIMUM_SIZE)
+
 # 注册路由
 app.include_router(repositories.router, prefix=settings.API_PREFIX)
 app.include_router(analysis.router, prefix=settings.API_PREFIX)
+app.include_router(crawls.router, prefix=settings.API_PREFIX)
+app.include_router(owners.router, prefix=settings.API_PREFIX)
+app.include_router(webhooks.router, prefix=settings.API_PREFIX)
+app.include_router(feed.router, prefix=settings.API_PREFIX)
+app.include_router(system.router, prefix=settings.API_PREFIX)
+app.include_router(graphql.router, prefix="/graphql")
 
 @app.get("/")
 async def root():
-    return {"message": "Welcome to RepoInsight API"} 
\ No newline at end of file
+    return {"message": "Welcome to RepoInsight API"} 
+
+if __name__ == "__main__":
+    import uvicorn
+
+    # HOST 设为 127.0.0.1 时只监听本机，适合放在反向代理之后
+    # 自动重启需要以导入路径传入应用
+    uvicorn.run(
+        "app.main:app" if settings.RELOAD else app,
+        host=settings.HOST,
+        port=settings.PORT,
+        reload=settings.RELOAD,
+        timeout_graceful_shutdown=settings.SHUTDOWN_TIMEOUT,
+    )
@@ -0,0 +1,19 @@
+from sqlalchemy import Column, Integer, String, Text, DateTime
+from sqlalchemy.sql import func
+from ..database import Base
+
+# 由 schema.sql 中 ai_analysis 的 AFTER INSERT OR UPDATE 触发器写入，应用代码只读
+class AIAnalysisHistory(Base):
+    __tablename__ = "ai_analysis_history"
+
+    id = Column(Integer, primary_key=True, index=True)
+    created_at = Column(DateTime(timezone=True), server_default=func.now())
+    updated_at = Column(DateTime(timezone=True), onupdate=func.now())
+    
+    url = Column(String(255), nullable=False, index=True)
+    content = Column(Text)
+    status = Column(String(20))
+    error_message = Column(Text)
+    analysis_type = Column(String(50))
+    model_version = Column(String(50))
+    tokens_used = Column(Integer)
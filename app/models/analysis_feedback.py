@@ -0,0 +1,16 @@
+from sqlalchemy import Column, Integer, String, Text, DateTime, ForeignKey
+from sqlalchemy.sql import func
+from ..database import Base
+
+class AnalysisFeedback(Base):
+    __tablename__ = "analysis_feedback"
+
+    id = Column(Integer, primary_key=True, index=True)
+    created_at = Column(DateTime(timezone=True), server_default=func.now())
+    updated_at = Column(DateTime(timezone=True), onupdate=func.now())
+    
+    analysis_id = Column(Integer, ForeignKey("ai_analysis.id", ondelete="CASCADE"), nullable=False, index=True)
+    # 记录评分时的模型版本，重新分析后仍能按模型统计
+    model_version = Column(String(50), index=True)
+    rating = Column(Integer, nullable=False)
+    comment = Column(Text)
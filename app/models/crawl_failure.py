@@ -0,0 +1,15 @@
+from sqlalchemy import Column, Integer, String, Text, DateTime, ForeignKey
+from sqlalchemy.sql import func
+from ..database import Base
+
+class CrawlFailure(Base):
+    __tablename__ = "crawl_failure"
+
+    id = Column(Integer, primary_key=True, index=True)
+    created_at = Column(DateTime(timezone=True), server_default=func.now())
+    updated_at = Column(DateTime(timezone=True), onupdate=func.now())
+    
+    crawl_history_id = Column(Integer, ForeignKey("crawl_history.id", ondelete="CASCADE"), nullable=False, index=True)
+    full_name = Column(String(255), nullable=False)
+    url = Column(String(255))
+    error_message = Column(Text)
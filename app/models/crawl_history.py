@@ -14,5 +14,10 @@ class CrawlHistory(Base):
     completed_at = Column(DateTime(timezone=True))
     total_repos = Column(Integer, default=0)
     processed_repos = Column(Integer, default=0)
+    inserted_repos = Column(Integer, default=0)
+    updated_repos = Column(Integer, default=0)
+    skipped_repos = Column(Integer, default=0)
+    failed_repos = Column(Integer, default=0)
+    rate_limit_remaining = Column(Integer)
     status = Column(String(20), default='running')
     error_message = Column(Text) 
\ No newline at end of file
@@ -1,7 +1,17 @@
-from sqlalchemy import Column, Integer, String, Text, Boolean, DateTime, ForeignKey
+from sqlalchemy import Column, Integer, BigInteger, String, Text, Boolean, DateTime, Float, ForeignKey
 from sqlalchemy.sql import func
 from ..database import Base
 
+# 仓库分类可选值，分类结果不在其中时记为 unknown；修改时同步 schema.sql 中的 normalize_repository_category
+REPOSITORY_CATEGORIES = (
+    'devtool',
+    'web-framework',
+    'ml',
+    'game',
+    'library',
+    'unknown',
+)
+
 class Repository(Base):
     __tablename__ = "repository"
 
@@ -9,23 +19,45 @@ class Repository(Base):
     created_at = Column(DateTime(timezone=True), server_default=func.now())
     updated_at = Column(DateTime(timezone=True), onupdate=func.now())
     deleted_at = Column(DateTime(timezone=True), nullable=True)
+    # 软删除原因，gone 表示 GitHub 上已删除或转为私有
+    deleted_reason = Column(String(20))
     
+    # GitHub 仓库的数字 id，改名后不变，用于匹配已入库的仓库
+    github_id = Column(BigInteger, unique=True)
     full_name = Column(String(255), unique=True, nullable=False)
     name = Column(String(255), nullable=False)
     owner = Column(String(255), nullable=False)
+    owner_avatar_url = Column(String(255))
+    owner_type = Column(String(20))
     description = Column(Text)
     url = Column(String(255), nullable=False)
+    homepage = Column(String(255))
     stars = Column(Integer, default=0)
     forks = Column(Integer, default=0)
     language = Column(String(50))
+    ecosystem = Column(String(100))
     topics = Column(Text)
+    topics_refreshed_at = Column(DateTime(timezone=True))
+    # 从 README 词频提取的主题，逗号分隔，与 GitHub topics 分开存储
+    derived_topics = Column(Text)
     readme = Column(Text)
+    # README 中是否包含对应章节，由 readme_sections.docs_fields 解析标题得到
+    has_install_docs = Column(Boolean, default=False)
+    has_usage_docs = Column(Boolean, default=False)
+    has_contributing_docs = Column(Boolean, default=False)
+    has_license_docs = Column(Boolean, default=False)
+    # GitHub 上的创建时间，created_at 是入库时间
+    github_created_at = Column(DateTime(timezone=True))
     last_pushed_at = Column(DateTime(timezone=True))
+    latest_release_tag = Column(String(100))
+    latest_release_at = Column(DateTime(timezone=True))
     is_archived = Column(Boolean, default=False)
     license = Column(String(100))
     default_branch = Column(String(100))
     open_issues = Column(Integer, default=0)
     watchers = Column(Integer, default=0)
+    contributor_count = Column(Integer)
+    contributors_updated_at = Column(DateTime(timezone=True))
     size = Column(Integer, default=0)
     has_issues = Column(Boolean, default=True)
     has_projects = Column(Boolean, default=True)
@@ -33,8 +65,19 @@ class Repository(Base):
     has_pages = Column(Boolean, default=False)
     has_downloads = Column(Boolean, default=True)
     is_template = Column(Boolean, default=False)
+    # 由 schema.sql 中的触发器在第一次分析成功时设置
+    first_analyzed_at = Column(DateTime(timezone=True))
     last_analyzed_at = Column(DateTime(timezone=True))
     analysis_status = Column(String(20), default='pending')
     search_keyword = Column(String(255))
     search_rank = Column(Integer)
-    last_crawled_at = Column(DateTime(timezone=True)) 
\ No newline at end of file
+    last_crawled_at = Column(DateTime(timezone=True))
+    # 首次入库和最近一次写入该仓库的爬取任务，由爬虫写入，手动添加的仓库为空
+    first_seen_crawl_id = Column(Integer, ForeignKey("crawl_history.id", ondelete="SET NULL"), index=True)
+    last_crawl_id = Column(Integer, ForeignKey("crawl_history.id", ondelete="SET NULL"), index=True)
+    reconciled_at = Column(DateTime(timezone=True))
+    category = Column(String(50), default='unknown')
+    # 活跃度评分 0~1，由 app/scores.py 计算
+    freshness_score = Column(Float)
+    # experimental/growing/mature/stale，由 app/scores.py 根据创建时间、star 数和最近推送计算
+    maturity = Column(String(20)) 
\ No newline at end of file
@@ -0,0 +1,14 @@
+from sqlalchemy import Column, Integer, Text, DateTime, ForeignKey
+from sqlalchemy.sql import func
+from ..database import Base
+
+class RepositoryChange(Base):
+    __tablename__ = "repository_change"
+
+    id = Column(Integer, primary_key=True, index=True)
+    created_at = Column(DateTime(timezone=True), server_default=func.now())
+    updated_at = Column(DateTime(timezone=True), onupdate=func.now())
+    
+    repo_id = Column(Integer, ForeignKey("repository.id", ondelete="CASCADE"), nullable=False, index=True)
+    # JSON 对象，字段名 -> {"old": 旧值, "new": 新值}
+    changes = Column(Text, nullable=False)
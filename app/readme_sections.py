@@ -0,0 +1,51 @@
+import re
+
+# 标题关键词到 Repository 字段的映射，一个标题可以同时命中多个章节
+SECTION_PATTERNS = {
+    "has_install_docs": re.compile(r"\b(install(ation|ing)?|setup|set up|getting started|quick ?start)\b"),
+    "has_usage_docs": re.compile(r"\b(usage|how to use|examples?|tutorial|quick ?start)\b"),
+    "has_contributing_docs": re.compile(r"\b(contribut(e|ing|ion|ions|ors?)|development)\b"),
+    "has_license_docs": re.compile(r"\b(licen[cs](e|ing)|copyright)\b"),
+}
+
+FENCE = re.compile(r"^ {0,3}(`{3,}|~{3,})")
+ATX_HEADING = re.compile(r"^ {0,3}#{1,6}\s+(.*?)\s*#*\s*$")
+SETEXT_UNDERLINE = re.compile(r"^ {0,3}(=+|-+)\s*$")
+HTML_HEADING = re.compile(r"<h[1-6][^>]*>(.*?)</h[1-6]>", re.I | re.S)
+HTML_TAG = re.compile(r"<[^>]+>")
+
+def headings(readme: str) -> list:
+    """按 Markdown 语法提取 README 中的标题文本（ATX、Setext 和 HTML 标题），跳过代码块"""
+    if not readme:
+        return []
+    result = []
+    fence = None
+    previous = ""
+    for line in readme.splitlines():
+        match = FENCE.match(line)
+        if match:
+            marker = match.group(1)
+            if fence is None:
+                fence = marker[0]
+            elif marker[0] == fence:
+                fence = None
+            previous = ""
+            continue
+        if fence is not None:
+            continue
+        atx = ATX_HEADING.match(line)
+        if atx:
+            result.append(atx.group(1))
+        elif previous.strip() and SETEXT_UNDERLINE.match(line):
+            result.append(previous.strip())
+        result.extend(HTML_HEADING.findall(line))
+        previous = line
+    return [HTML_TAG.sub(" ", h).strip().lower() for h in result]
+
+def docs_fields(readme: str) -> dict:
+    """根据 README 标题判断是否包含安装、使用、贡献和许可证章节"""
+    titles = headings(readme)
+    return {
+        field: any(pattern.search(t) for t in titles)
+        for field, pattern in SECTION_PATTERNS.items()
+    }
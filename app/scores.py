@@ -0,0 +1,48 @@
+import math
+from datetime import datetime, timezone
+from .config import settings
+
+def freshness_score(repo, now=None) -> float:
+    """活跃度评分，取值 0~1：
+    push 权重 * 0.5^(距最近推送天数 / 半衰期) + star 权重 * min(log10(stars + 1) / 5, 1) - 归档扣分，结果截断到 [0, 1]
+    """
+    now = now or datetime.now(timezone.utc)
+    if repo.last_pushed_at:
+        days = max((now - repo.last_pushed_at).total_seconds() / 86400, 0)
+        recency = 0.5 ** (days / settings.FRESHNESS_HALF_LIFE_DAYS)
+    else:
+        recency = 0.0
+    # 10 万 star 时满分
+    popularity = min(math.log10((repo.stars or 0) + 1) / 5, 1.0)
+    score = settings.FRESHNESS_PUSH_WEIGHT * recency + settings.FRESHNESS_STARS_WEIGHT * popularity
+    if repo.is_archived:
+        score -= settings.FRESHNESS_ARCHIVED_PENALTY
+    return round(min(max(score, 0.0), 1.0), 4)
+
+def maturity(repo, now=None):
+    """按顺序判断：已归档或超过 MATURITY_STALE_DAYS 未推送为 stale；
+    创建不足 MATURITY_EXPERIMENTAL_MAX_AGE_DAYS 或 star 少于 MATURITY_GROWING_MIN_STARS 为 experimental；
+    创建满 MATURITY_MATURE_MIN_AGE_DAYS 且 star 不少于 MATURITY_MATURE_MIN_STARS 为 mature；其余为 growing。
+    创建时间未知时返回 None
+    """
+    now = now or datetime.now(timezone.utc)
+    if repo.is_archived:
+        return 'stale'
+    if repo.last_pushed_at and (now - repo.last_pushed_at).days > settings.MATURITY_STALE_DAYS:
+        return 'stale'
+    if not repo.github_created_at:
+        return None
+    age_days = (now - repo.github_created_at).days
+    stars = repo.stars or 0
+    if age_days < settings.MATURITY_EXPERIMENTAL_MAX_AGE_DAYS or stars < settings.MATURITY_GROWING_MIN_STARS:
+        return 'experimental'
+    if age_days >= settings.MATURITY_MATURE_MIN_AGE_DAYS and stars >= settings.MATURITY_MATURE_MIN_STARS:
+        return 'mature'
+    return 'growing'
+
+def score_fields(repo, now=None) -> dict:
+    """根据已入库字段计算的派生字段，不请求 GitHub"""
+    return {
+        "freshness_score": freshness_score(repo, now),
+        "maturity": maturity(repo, now),
+    }
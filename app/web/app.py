@@ -13,6 +13,10 @@ st.set_page_config(
 # 设置API基础URL
 API_BASE_URL = "http://localhost:8000/api/v1"
 
+def list_items(payload):
+    # 列表接口返回 {data, meta}，关闭 API_ENVELOPE 时 /repositories 和 /repositories/top 为裸数组
+    return payload["data"] if isinstance(payload, dict) else payload
+
 # 侧边栏导航
 page = st.sidebar.radio(
     "导航",
@@ -28,7 +32,7 @@ if page == "项目搜索":
             with st.spinner("正在搜索..."):
                 response = requests.get(f"{API_BASE_URL}/repositories", params={"q": search_query})
                 if response.status_code == 200:
-                    repos = response.json()
+                    repos = list_items(response.json())
                     for repo in repos:
                         with st.expander(f"{repo['full_name']} ({repo['stars']} ⭐)"):
                             st.write(f"**描述:** {repo['description']}")
@@ -57,7 +61,7 @@ elif page == "热门项目":
         st.subheader("按星标数")
         response = requests.get(f"{API_BASE_URL}/repositories/top", params={"sort": "stars"})
         if response.status_code == 200:
-            repos = response.json()
+            repos = list_items(response.json())
             for repo in repos:
                 with st.expander(f"{repo['full_name']} ({repo['stars']} ⭐)"):
                     st.write(f"**描述:** {repo['description']}")
@@ -70,7 +74,7 @@ elif page == "热门项目":
         st.subheader("最近更新")
         response = requests.get(f"{API_BASE_URL}/repositories/top", params={"sort": "updated"})
         if response.status_code == 200:
-            repos = response.json()
+            repos = list_items(response.json())
             for repo in repos:
                 with st.expander(f"{repo['full_name']} ({repo['stars']} ⭐)"):
                     st.write(f"**描述:** {repo['description']}")
@@ -85,7 +89,7 @@ elif page == "已分析项目":
     st.info("只展示有AI分析结果的项目")
     response = requests.get(f"{API_BASE_URL}/repositories")
     if response.status_code == 200:
-        repos = response.json()
+        repos = list_items(response.json())
         analyzed_repos = [r for r in repos if r.get('analysis') and r['analysis'].get('content')]
         if not analyzed_repos:
             st.warning("暂无已分析项目")
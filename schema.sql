@@ -7,22 +7,39 @@ CREATE TABLE IF NOT EXISTS repository (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_reason VARCHAR(20),
+    github_id BIGINT,
     full_name VARCHAR(255) UNIQUE NOT NULL,
     name VARCHAR(255) NOT NULL,
     owner VARCHAR(255) NOT NULL,
+    owner_avatar_url VARCHAR(255),
+    owner_type VARCHAR(20),
     description TEXT,
     url VARCHAR(255) NOT NULL,
+    homepage VARCHAR(255),
     stars INTEGER DEFAULT 0,
     forks INTEGER DEFAULT 0,
     language VARCHAR(50),
+    ecosystem VARCHAR(100),
     topics TEXT,
+    topics_refreshed_at TIMESTAMP WITH TIME ZONE,
+    derived_topics TEXT,
     readme TEXT,
+    has_install_docs BOOLEAN DEFAULT FALSE,
+    has_usage_docs BOOLEAN DEFAULT FALSE,
+    has_contributing_docs BOOLEAN DEFAULT FALSE,
+    has_license_docs BOOLEAN DEFAULT FALSE,
+    github_created_at TIMESTAMP WITH TIME ZONE,
     last_pushed_at TIMESTAMP WITH TIME ZONE,
+    latest_release_tag VARCHAR(100),
+    latest_release_at TIMESTAMP WITH TIME ZONE,
     is_archived BOOLEAN DEFAULT FALSE,
     license VARCHAR(100),
     default_branch VARCHAR(100),
     open_issues INTEGER DEFAULT 0,
     watchers INTEGER DEFAULT 0,
+    contributor_count INTEGER,
+    contributors_updated_at TIMESTAMP WITH TIME ZONE,
     size INTEGER DEFAULT 0,
     has_issues BOOLEAN DEFAULT TRUE,
     has_projects BOOLEAN DEFAULT TRUE,
@@ -30,15 +47,50 @@ CREATE TABLE IF NOT EXISTS repository (
     has_pages BOOLEAN DEFAULT FALSE,
     has_downloads BOOLEAN DEFAULT TRUE,
     is_template BOOLEAN DEFAULT FALSE,
+    first_analyzed_at TIMESTAMP WITH TIME ZONE,
     last_analyzed_at TIMESTAMP WITH TIME ZONE,
     analysis_status VARCHAR(20) DEFAULT 'pending',
     search_keyword VARCHAR(255),
     search_rank INTEGER,
-    last_crawled_at TIMESTAMP WITH TIME ZONE
+    last_crawled_at TIMESTAMP WITH TIME ZONE,
+    first_seen_crawl_id INTEGER,
+    last_crawl_id INTEGER,
+    reconciled_at TIMESTAMP WITH TIME ZONE,
+    category VARCHAR(50) DEFAULT 'unknown',
+    freshness_score DOUBLE PRECISION,
+    maturity VARCHAR(20)
 );
 
+-- 补齐已有数据库缺少的列（schema.sql 可重复执行：psql -f schema.sql）
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS deleted_reason VARCHAR(20);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS owner_avatar_url VARCHAR(255);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS owner_type VARCHAR(20);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS homepage VARCHAR(255);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS ecosystem VARCHAR(100);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS derived_topics TEXT;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS has_install_docs BOOLEAN DEFAULT FALSE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS has_usage_docs BOOLEAN DEFAULT FALSE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS has_contributing_docs BOOLEAN DEFAULT FALSE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS has_license_docs BOOLEAN DEFAULT FALSE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS latest_release_tag VARCHAR(100);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS latest_release_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS contributor_count INTEGER;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS contributors_updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS first_analyzed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS category VARCHAR(50) DEFAULT 'unknown';
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS freshness_score DOUBLE PRECISION;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS maturity VARCHAR(20);
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS topics_refreshed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS reconciled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS github_created_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS first_seen_crawl_id INTEGER;
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS last_crawl_id INTEGER;
+-- 已有数据的 github_id 由 /repositories/reconcile 逐步回填
+ALTER TABLE repository ADD COLUMN IF NOT EXISTS github_id BIGINT;
+
 -- 创建索引
 CREATE INDEX IF NOT EXISTS idx_repository_full_name ON repository(full_name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_repository_github_id ON repository(github_id);
 CREATE INDEX IF NOT EXISTS idx_repository_stars ON repository(stars DESC);
 CREATE INDEX IF NOT EXISTS idx_repository_language ON repository(language);
 CREATE INDEX IF NOT EXISTS idx_repository_last_pushed_at ON repository(last_pushed_at DESC);
@@ -46,6 +98,36 @@ CREATE INDEX IF NOT EXISTS idx_repository_deleted_at ON repository(deleted_at);
 CREATE INDEX IF NOT EXISTS idx_repository_analysis_status ON repository(analysis_status);
 CREATE INDEX IF NOT EXISTS idx_repository_search_keyword ON repository(search_keyword);
 CREATE INDEX IF NOT EXISTS idx_repository_last_analyzed_at ON repository(last_analyzed_at);
+CREATE INDEX IF NOT EXISTS idx_repository_category ON repository(category);
+CREATE INDEX IF NOT EXISTS idx_repository_maturity ON repository(maturity);
+CREATE INDEX IF NOT EXISTS idx_repository_freshness_score ON repository(freshness_score DESC NULLS LAST);
+CREATE INDEX IF NOT EXISTS idx_repository_topics_refreshed_at ON repository(topics_refreshed_at NULLS FIRST);
+CREATE INDEX IF NOT EXISTS idx_repository_reconciled_at ON repository(reconciled_at NULLS FIRST);
+
+-- 创建仓库变更记录表
+CREATE TABLE IF NOT EXISTS repository_change (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    repo_id INTEGER NOT NULL REFERENCES repository(id) ON DELETE CASCADE,
+    changes TEXT NOT NULL
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_repository_change_repo_id_created_at ON repository_change(repo_id, created_at DESC);
+
+-- 创建用户标签表（部署内自定义标签，与 GitHub topics 无关）
+CREATE TABLE IF NOT EXISTS label (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    repo_id INTEGER NOT NULL REFERENCES repository(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE(repo_id, name)
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_label_name ON label(name);
 
 -- 创建 AI 分析表
 CREATE TABLE IF NOT EXISTS ai_analysis (
@@ -67,6 +149,38 @@ CREATE INDEX IF NOT EXISTS idx_ai_analysis_url ON ai_analysis(url);
 CREATE INDEX IF NOT EXISTS idx_ai_analysis_status ON ai_analysis(status);
 CREATE INDEX IF NOT EXISTS idx_ai_analysis_analysis_type ON ai_analysis(analysis_type);
 
+-- 创建 AI 分析历史表（每次分析追加一行，ai_analysis 只保留最新结果）
+CREATE TABLE IF NOT EXISTS ai_analysis_history (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    url VARCHAR(255) NOT NULL,
+    content TEXT,
+    status VARCHAR(20),
+    error_message TEXT,
+    analysis_type VARCHAR(50),
+    model_version VARCHAR(50),
+    tokens_used INTEGER
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_ai_analysis_history_url_created_at ON ai_analysis_history(url, created_at DESC);
+
+-- 创建分析评分表
+CREATE TABLE IF NOT EXISTS analysis_feedback (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    analysis_id INTEGER NOT NULL REFERENCES ai_analysis(id) ON DELETE CASCADE,
+    model_version VARCHAR(50),
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_analysis_feedback_analysis_id ON analysis_feedback(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_analysis_feedback_model_version ON analysis_feedback(model_version);
+
 -- 创建爬取历史表
 CREATE TABLE IF NOT EXISTS crawl_history (
     id SERIAL PRIMARY KEY,
@@ -77,15 +191,75 @@ CREATE TABLE IF NOT EXISTS crawl_history (
     completed_at TIMESTAMP WITH TIME ZONE,
     total_repos INTEGER DEFAULT 0,
     processed_repos INTEGER DEFAULT 0,
+    inserted_repos INTEGER DEFAULT 0,
+    updated_repos INTEGER DEFAULT 0,
+    skipped_repos INTEGER DEFAULT 0,
+    failed_repos INTEGER DEFAULT 0,
+    rate_limit_remaining INTEGER,
     status VARCHAR(20) DEFAULT 'running',
     error_message TEXT
 );
 
+-- 补齐已有数据库缺少的列
+ALTER TABLE crawl_history ADD COLUMN IF NOT EXISTS inserted_repos INTEGER DEFAULT 0;
+ALTER TABLE crawl_history ADD COLUMN IF NOT EXISTS updated_repos INTEGER DEFAULT 0;
+ALTER TABLE crawl_history ADD COLUMN IF NOT EXISTS skipped_repos INTEGER DEFAULT 0;
+ALTER TABLE crawl_history ADD COLUMN IF NOT EXISTS failed_repos INTEGER DEFAULT 0;
+ALTER TABLE crawl_history ADD COLUMN IF NOT EXISTS rate_limit_remaining INTEGER;
+
+-- repository 先于 crawl_history 创建，爬取任务外键在这里补上
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'repository_first_seen_crawl_id_fkey') THEN
+        ALTER TABLE repository ADD CONSTRAINT repository_first_seen_crawl_id_fkey
+            FOREIGN KEY (first_seen_crawl_id) REFERENCES crawl_history(id) ON DELETE SET NULL;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'repository_last_crawl_id_fkey') THEN
+        ALTER TABLE repository ADD CONSTRAINT repository_last_crawl_id_fkey
+            FOREIGN KEY (last_crawl_id) REFERENCES crawl_history(id) ON DELETE SET NULL;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_repository_first_seen_crawl_id ON repository(first_seen_crawl_id);
+CREATE INDEX IF NOT EXISTS idx_repository_last_crawl_id ON repository(last_crawl_id);
+
+-- 回填加列之前入库的仓库：入库时间落在同关键词爬取窗口内的视为该次爬取首次发现，可重复执行
+UPDATE repository r
+SET first_seen_crawl_id = ch.id
+FROM crawl_history ch
+WHERE r.first_seen_crawl_id IS NULL
+    AND r.search_keyword = ch.keyword
+    AND r.created_at >= ch.started_at
+    AND r.created_at <= COALESCE(ch.completed_at, ch.started_at);
+
+-- 同理，最近爬取时间落在同关键词爬取窗口内的视为由该次爬取最近写入
+UPDATE repository r
+SET last_crawl_id = ch.id
+FROM crawl_history ch
+WHERE r.last_crawl_id IS NULL
+    AND r.search_keyword = ch.keyword
+    AND r.last_crawled_at >= ch.started_at
+    AND r.last_crawled_at <= ch.completed_at;
+
 -- 创建索引
 CREATE INDEX IF NOT EXISTS idx_crawl_history_keyword ON crawl_history(keyword);
 CREATE INDEX IF NOT EXISTS idx_crawl_history_status ON crawl_history(status);
 CREATE INDEX IF NOT EXISTS idx_crawl_history_started_at ON crawl_history(started_at);
 
+-- 创建爬取失败记录表（重试耗尽仍未入库的仓库）
+CREATE TABLE IF NOT EXISTS crawl_failure (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    crawl_history_id INTEGER NOT NULL REFERENCES crawl_history(id) ON DELETE CASCADE,
+    full_name VARCHAR(255) NOT NULL,
+    url VARCHAR(255),
+    error_message TEXT
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_crawl_failure_crawl_history_id ON crawl_failure(crawl_history_id);
+
 -- 创建每日推送进度表
 CREATE TABLE IF NOT EXISTS daily_push_progress (
     id SERIAL PRIMARY KEY,
@@ -128,4 +302,84 @@ CREATE TRIGGER update_ai_analysis_updated_at
 CREATE TRIGGER update_crawl_history_updated_at
     BEFORE UPDATE ON crawl_history
     FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column(); 
\ No newline at end of file
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- 每次写入分析结果时追加一行到 ai_analysis_history
+CREATE OR REPLACE FUNCTION append_ai_analysis_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- 只有 updated_at 变化的更新不记录
+    IF TG_OP = 'UPDATE'
+        AND NEW.content IS NOT DISTINCT FROM OLD.content
+        AND NEW.status IS NOT DISTINCT FROM OLD.status
+        AND NEW.error_message IS NOT DISTINCT FROM OLD.error_message
+        AND NEW.model_version IS NOT DISTINCT FROM OLD.model_version THEN
+        RETURN NEW;
+    END IF;
+    INSERT INTO ai_analysis_history (url, content, status, error_message, analysis_type, model_version, tokens_used)
+    VALUES (NEW.url, NEW.content, NEW.status, NEW.error_message, NEW.analysis_type, NEW.model_version, NEW.tokens_used);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS append_ai_analysis_history ON ai_analysis;
+
+CREATE TRIGGER append_ai_analysis_history
+    AFTER INSERT OR UPDATE ON ai_analysis
+    FOR EACH ROW
+    EXECUTE FUNCTION append_ai_analysis_history();
+
+-- 回填触发器安装前已有的分析结果，每个 url 没有历史时补一行，可重复执行
+INSERT INTO ai_analysis_history (created_at, url, content, status, error_message, analysis_type, model_version, tokens_used)
+SELECT COALESCE(a.updated_at, a.created_at), a.url, a.content, a.status, a.error_message, a.analysis_type, a.model_version, a.tokens_used
+FROM ai_analysis a
+WHERE NOT EXISTS (SELECT 1 FROM ai_analysis_history h WHERE h.url = a.url);
+
+-- 首次分析时间只在第一次分析成功（analysis_status = 'completed'）时写入，之后保持不变
+CREATE OR REPLACE FUNCTION set_first_analyzed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        NEW.first_analyzed_at = OLD.first_analyzed_at;
+    END IF;
+    IF NEW.first_analyzed_at IS NULL AND NEW.analysis_status = 'completed' THEN
+        NEW.first_analyzed_at = NEW.last_analyzed_at;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS set_repository_first_analyzed_at ON repository;
+
+CREATE TRIGGER set_repository_first_analyzed_at
+    BEFORE INSERT OR UPDATE ON repository
+    FOR EACH ROW
+    EXECUTE FUNCTION set_first_analyzed_at();
+
+-- 回填加列之前已分析成功的仓库，可重复执行
+UPDATE repository
+SET first_analyzed_at = last_analyzed_at
+WHERE first_analyzed_at IS NULL AND last_analyzed_at IS NOT NULL AND analysis_status = 'completed';
+
+-- 分类只允许 REPOSITORY_CATEGORIES（app/models/repository.py）中的取值，空值和枚举外的值一律存为 unknown
+CREATE OR REPLACE FUNCTION normalize_repository_category()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.category IS NULL OR NEW.category NOT IN ('devtool', 'web-framework', 'ml', 'game', 'library', 'unknown') THEN
+        NEW.category = 'unknown';
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS normalize_repository_category ON repository;
+
+CREATE TRIGGER normalize_repository_category
+    BEFORE INSERT OR UPDATE ON repository
+    FOR EACH ROW
+    EXECUTE FUNCTION normalize_repository_category();
+
+-- 回填已有数据，可重复执行
+UPDATE repository
+SET category = 'unknown'
+WHERE category IS NULL OR category NOT IN ('devtool', 'web-framework', 'ml', 'game', 'library', 'unknown');
@@ -0,0 +1,25 @@
+import os
+import pytest
+
+# app.config 在导入时加载全局 settings，先补齐必填项，避免依赖本地 .env
+os.environ.setdefault("GITHUB_TOKEN", "test-github-token")
+os.environ.setdefault("DEEPSEEK_API_KEY", "test-deepseek-key")
+
+@pytest.fixture
+def session_factory():
+    # 用内存 SQLite 代替 Postgres，只覆盖不依赖 Postgres 特性的写入逻辑
+    from sqlalchemy import create_engine
+    from sqlalchemy.orm import sessionmaker
+    from sqlalchemy.pool import StaticPool
+    from app.database import Base
+    import app.models.crawl_history  # noqa: F401  repository 的外键依赖
+    engine = create_engine("sqlite://", connect_args={"check_same_thread": False}, poolclass=StaticPool)
+    Base.metadata.create_all(engine)
+    yield sessionmaker(autocommit=False, autoflush=False, bind=engine)
+    engine.dispose()
+
+@pytest.fixture
+def db(session_factory):
+    session = session_factory()
+    yield session
+    session.close()
@@ -0,0 +1,30 @@
+import json
+from types import SimpleNamespace
+from app import github
+from app.api.routes.repositories import RepositoryCreate, create_repository
+from app.changes import diff_fields
+from app.models.repository import Repository
+from app.models.repository_change import RepositoryChange
+
+def test_diff_fields_skips_unchanged_and_ignored():
+    repo = SimpleNamespace(stars=10, description="same", contributors_updated_at=None, github_id=None)
+    changes = diff_fields(repo, {"stars": 12, "description": "same", "contributors_updated_at": "now", "github_id": 1})
+    assert changes == {"stars": {"old": 10, "new": 12}}
+
+def test_create_on_existing_repo_records_changes(db, monkeypatch):
+    repo = Repository(github_id=1, full_name="alice/tool", name="tool", owner="alice", url="https://github.com/alice/tool", stars=10)
+    db.add(repo)
+    db.commit()
+    monkeypatch.setattr(github, "get_repository", lambda owner, name: {
+        "id": 1,
+        "full_name": "alice/tool",
+        "name": "tool",
+        "owner": {"login": "alice"},
+        "html_url": "https://github.com/alice/tool",
+        "stargazers_count": 60,
+    })
+    create_repository(RepositoryCreate(owner="alice", name="tool"), db)
+
+    records = db.query(RepositoryChange).filter(RepositoryChange.repo_id == repo.id).all()
+    assert len(records) == 1
+    assert json.loads(records[0].changes)["stars"] == {"old": 10, "new": 60}
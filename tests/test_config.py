@@ -0,0 +1,74 @@
+import pytest
+from pydantic import ValidationError
+from app.config import load_settings
+
+REQUIRED = ("GITHUB_TOKEN", "DEEPSEEK_API_KEY")
+
+@pytest.fixture
+def env_file(tmp_path):
+    def write(**values):
+        path = tmp_path / "test.env"
+        path.write_text("".join(f"{k}={v}\n" for k, v in values.items()))
+        return str(path)
+    return write
+
+@pytest.fixture(autouse=True)
+def clean_env(monkeypatch):
+    # 只保留 conftest 设置的必填项，避免开发机上的环境变量影响结果
+    for name in ("CONFIG_PATH", "DB_HOST", "DB_PORT", "DB_SSLMODE", "DB_POOL_SIZE", "PORT", "DEFAULT_PAGE_SIZE", "MAX_PAGE_SIZE"):
+        monkeypatch.delenv(name, raising=False)
+
+def test_load_from_path(env_file):
+    settings = load_settings(env_file(DB_HOST="db.internal", DB_PORT="6543", PORT="9000"))
+    assert settings.DB_HOST == "db.internal"
+    assert settings.DB_PORT == 6543
+    assert settings.PORT == 9000
+
+def test_load_from_config_path_env(monkeypatch, env_file):
+    monkeypatch.setenv("CONFIG_PATH", env_file(DB_HOST="from-config-path"))
+    assert load_settings().DB_HOST == "from-config-path"
+
+def test_explicit_path_overrides_config_path_env(monkeypatch, env_file, tmp_path):
+    other = tmp_path / "other.env"
+    other.write_text("DB_HOST=from-env-var-file\n")
+    monkeypatch.setenv("CONFIG_PATH", str(other))
+    assert load_settings(env_file(DB_HOST="from-argument")).DB_HOST == "from-argument"
+
+def test_env_overrides_file(monkeypatch, env_file):
+    monkeypatch.setenv("DB_HOST", "from-env")
+    assert load_settings(env_file(DB_HOST="from-file")).DB_HOST == "from-env"
+
+def test_missing_file_uses_defaults(tmp_path):
+    settings = load_settings(str(tmp_path / "missing.env"))
+    assert settings.DB_HOST == "localhost"
+    assert settings.DB_SSLMODE == "prefer"
+
+@pytest.mark.parametrize("name", REQUIRED)
+def test_missing_required_field(monkeypatch, env_file, name):
+    monkeypatch.delenv(name)
+    with pytest.raises(ValidationError, match=name):
+        load_settings(env_file(DB_HOST="localhost"))
+
+def test_required_field_from_file(monkeypatch, env_file):
+    monkeypatch.delenv("GITHUB_TOKEN")
+    assert load_settings(env_file(GITHUB_TOKEN="file-token")).GITHUB_TOKEN == "file-token"
+
+@pytest.mark.parametrize("name,value", [
+    ("DB_SSLMODE", "sometimes"),
+    ("DB_PORT", "not-a-number"),
+    ("DB_POOL_SIZE", "0"),
+])
+def test_invalid_value(env_file, name, value):
+    with pytest.raises(ValidationError, match=name):
+        load_settings(env_file(**{name: value}))
+
+def test_default_page_size_above_max(env_file):
+    with pytest.raises(ValidationError, match="DEFAULT_PAGE_SIZE"):
+        load_settings(env_file(DEFAULT_PAGE_SIZE="5000", MAX_PAGE_SIZE="100"))
+
+def test_redacted_masks_secret_suffixes_only(env_file):
+    redacted = load_settings(env_file(DB_PASSWORD="hunter2", AI_PRICE_PER_MILLION_TOKENS="2.5")).redacted()
+    assert redacted["GITHUB_TOKEN"] == "***"
+    assert redacted["DEEPSEEK_API_KEY"] == "***"
+    assert redacted["DB_PASSWORD"] == "***"
+    assert redacted["AI_PRICE_PER_MILLION_TOKENS"] == 2.5
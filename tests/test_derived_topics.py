@@ -0,0 +1,35 @@
+from app.derived_topics import extract_topics
+
+def test_empty_readme():
+    assert extract_topics("") == []
+    assert extract_topics(None) == []
+
+def test_ranks_by_frequency_then_alphabetically():
+    readme = "kafka kafka kafka stream stream broker broker"
+    assert extract_topics(readme) == ["kafka", "broker", "stream"]
+
+def test_min_count_and_limit():
+    readme = "kafka kafka stream stream broker"
+    assert extract_topics(readme) == ["kafka", "stream"]
+    assert extract_topics(readme, limit=1) == ["kafka"]
+    assert extract_topics(readme, min_count=1) == ["kafka", "stream", "broker"]
+
+def test_skips_stopwords():
+    readme = "install install usage usage the the kafka kafka"
+    assert extract_topics(readme) == ["kafka"]
+
+def test_skips_fenced_code_and_inline_noise():
+    readme = (
+        "kafka kafka\n"
+        "```\nsecret secret secret\n```\n"
+        "`inline inline` https://example.com/secret <secret> ![secret](secret.png)\n"
+    )
+    assert extract_topics(readme) == ["kafka"]
+
+def test_strips_hyphens_and_rejects_short_words():
+    readme = "-ab- -ab- -ab- -grpc- -grpc- real-time real-time"
+    assert extract_topics(readme) == ["grpc", "real-time"]
+
+def test_skips_numbers():
+    readme = "2024 2024 2024 kafka kafka"
+    assert extract_topics(readme) == ["kafka"]
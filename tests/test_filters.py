@@ -0,0 +1,21 @@
+import pytest
+from pydantic import ValidationError
+from app.api.routes.repositories import LabelCreate, contains_item, escape_like
+from app.models.repository import Repository
+
+def test_escape_like_escapes_wildcards():
+    assert escape_like("c%_\\x") == "c\\%\\_\\\\x"
+    assert escape_like("machine-learning") == "machine-learning"
+
+def test_contains_item_uses_escape_clause():
+    expr = contains_item(Repository.topics, "100%")
+    assert expr.right.value == "%,100\\%,%"
+    assert expr.modifiers["escape"] == "\\"
+
+def test_label_name_is_stripped():
+    assert LabelCreate(name="  needs review ").name == "needs review"
+
+@pytest.mark.parametrize("name", ["", "   ", "a/b", "tag?"])
+def test_label_name_rejected(name):
+    with pytest.raises(ValidationError):
+        LabelCreate(name=name)
@@ -0,0 +1,50 @@
+import pytest
+from app import github
+
+class FakeClock:
+    def __init__(self):
+        self.now = 0.0
+        self.sleeps = []
+
+    def monotonic(self):
+        return self.now
+
+    def sleep(self, seconds):
+        self.sleeps.append(seconds)
+        self.now += seconds
+
+@pytest.fixture
+def clock(monkeypatch):
+    fake = FakeClock()
+    monkeypatch.setattr(github.time, "monotonic", fake.monotonic)
+    monkeypatch.setattr(github.time, "sleep", fake.sleep)
+    return fake
+
+def test_token_bucket_allows_burst_then_waits(clock):
+    bucket = github.TokenBucket(2)
+    bucket.acquire()
+    bucket.acquire()
+    assert clock.sleeps == []
+    bucket.acquire()
+    assert clock.sleeps == [pytest.approx(0.5)]
+
+def test_token_bucket_refills_over_time(clock):
+    bucket = github.TokenBucket(1)
+    bucket.acquire()
+    clock.now += 1
+    bucket.acquire()
+    assert clock.sleeps == []
+
+def test_token_bucket_zero_rate_is_unlimited(clock):
+    bucket = github.TokenBucket(0)
+    for _ in range(100):
+        bucket.acquire()
+    assert clock.sleeps == []
+
+def test_token_bucket_set_rate(clock):
+    bucket = github.TokenBucket(1)
+    bucket.acquire()
+    bucket.set_rate(4)
+    for _ in range(4):
+        bucket.acquire()
+    assert clock.sleeps == []
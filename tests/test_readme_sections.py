@@ -0,0 +1,44 @@
+from app.readme_sections import docs_fields, headings
+
+def test_atx_headings():
+    readme = "# Project ##\nintro\n### Getting Started\n#not-a-heading\n"
+    assert headings(readme) == ["project", "getting started"]
+
+def test_setext_headings():
+    readme = "Installation\n============\n\ntext\nUsage\n-----\n\n---\n"
+    assert headings(readme) == ["installation", "usage"]
+
+def test_html_headings():
+    readme = '<h1 align="center">My <b>Tool</b></h1>\n<h2>License</h2>\n'
+    assert headings(readme) == ["my  tool", "license"]
+
+def test_fenced_code_is_skipped():
+    readme = (
+        "# Usage\n"
+        "```bash\n# install deps\nInstall\n=======\n```\n"
+        "~~~\n```\n# still code\n~~~\n"
+        "## Contributing\n"
+    )
+    assert headings(readme) == ["usage", "contributing"]
+
+def test_docs_fields():
+    readme = "# Quick Start\nrun it\n\nContributors\n------------\n<h2>Copyright</h2>\n"
+    assert docs_fields(readme) == {
+        "has_install_docs": True,
+        "has_usage_docs": True,
+        "has_contributing_docs": True,
+        "has_license_docs": True,
+    }
+
+def test_docs_fields_ignores_body_text_and_code():
+    readme = "This project has no install guide.\n```\n# License\n```\n"
+    assert docs_fields(readme) == {
+        "has_install_docs": False,
+        "has_usage_docs": False,
+        "has_contributing_docs": False,
+        "has_license_docs": False,
+    }
+
+def test_empty_readme():
+    assert headings("") == []
+    assert not any(docs_fields(None).values())
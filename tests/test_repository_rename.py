@@ -0,0 +1,94 @@
+import pytest
+from fastapi import HTTPException
+from app import github
+from app.api.routes import webhooks
+from app.api.routes.repositories import RepositoryCreate, create_repository
+from app.models.ai_analysis import AIAnalysis
+from app.models.label import Label
+from app.models.repository import Repository
+
+def github_repo(github_id, full_name):
+    owner, name = full_name.split("/")
+    return {
+        "id": github_id,
+        "full_name": full_name,
+        "name": name,
+        "owner": {"login": owner},
+        "html_url": f"https://github.com/{full_name}",
+        "stargazers_count": 10,
+    }
+
+def add_repo(db, github_id, full_name):
+    owner, name = full_name.split("/")
+    repo = Repository(github_id=github_id, full_name=full_name, name=name, owner=owner, url=f"https://github.com/{full_name}")
+    db.add(repo)
+    db.commit()
+    return repo
+
+@pytest.fixture
+def tracked(db):
+    repo = add_repo(db, 1, "alice/old")
+    db.add(Label(repo_id=repo.id, name="to-try"))
+    db.add(AIAnalysis(url=repo.url, content="analysis", status="completed"))
+    db.commit()
+    return repo
+
+def test_create_with_old_name_updates_renamed_repo(db, tracked, monkeypatch):
+    # GitHub 对旧名字返回 301，跟随后拿到的是新名字
+    monkeypatch.setattr(github, "get_repository", lambda owner, name: github_repo(1, "alice/new"))
+    result = create_repository(RepositoryCreate(owner="alice", name="old"), db)
+
+    assert db.query(Repository).count() == 1
+    assert result["id"] == tracked.id
+    assert result["full_name"] == "alice/new"
+    assert result["analysis"]["content"] == "analysis"
+    assert [l.name for l in db.query(Label).filter(Label.repo_id == tracked.id)] == ["to-try"]
+
+def test_create_does_not_overwrite_when_old_name_is_reused(db, tracked, monkeypatch):
+    # 原仓库改名后，另一个仓库用了旧名字
+    monkeypatch.setattr(github, "get_repository", lambda owner, name: github_repo(2, "alice/old"))
+    with pytest.raises(HTTPException) as exc:
+        create_repository(RepositoryCreate(owner="alice", name="old"), db)
+
+    assert exc.value.status_code == 409
+    db.expire_all()
+    assert db.query(Repository).count() == 1
+    assert db.get(Repository, tracked.id).github_id == 1
+
+def test_create_backfills_github_id_for_legacy_row(db, monkeypatch):
+    legacy = add_repo(db, None, "alice/old")
+    monkeypatch.setattr(github, "get_repository", lambda owner, name: github_repo(1, "alice/new"))
+    create_repository(RepositoryCreate(owner="alice", name="old"), db)
+
+    db.expire_all()
+    repo = db.get(Repository, legacy.id)
+    assert (repo.github_id, repo.full_name) == (1, "alice/new")
+    assert db.query(Repository).count() == 1
+
+def test_create_requeues_tracked_repo_for_analysis(db, tracked, monkeypatch):
+    tracked.analysis_status = "completed"
+    db.commit()
+    monkeypatch.setattr(github, "get_repository", lambda owner, name: github_repo(1, "alice/old"))
+    create_repository(RepositoryCreate(owner="alice", name="old"), db)
+
+    db.expire_all()
+    assert db.get(Repository, tracked.id).analysis_status == "pending"
+
+def test_recrawl_follows_rename_by_github_id(db, tracked, session_factory, monkeypatch):
+    monkeypatch.setattr(webhooks, "SessionLocal", session_factory)
+    monkeypatch.setattr(github, "get_repository_by_id", lambda github_id: github_repo(github_id, "alice/new"))
+    webhooks.recrawl_repository(tracked.id)
+
+    db.expire_all()
+    repo = db.get(Repository, tracked.id)
+    assert repo.full_name == "alice/new"
+    assert db.query(AIAnalysis).filter(AIAnalysis.url == repo.url).count() == 1
+
+def test_recrawl_skips_full_name_collision(db, tracked, session_factory, monkeypatch):
+    add_repo(db, 2, "alice/new")
+    monkeypatch.setattr(webhooks, "SessionLocal", session_factory)
+    monkeypatch.setattr(github, "get_repository_by_id", lambda github_id: github_repo(github_id, "alice/new"))
+    webhooks.recrawl_repository(tracked.id)
+
+    db.expire_all()
+    assert db.get(Repository, tracked.id).full_name == "alice/old"